@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ahrtolia/goboot/pkg/config"
+)
+
+const configUsage = `Usage: goboot config <command> [flags]
+
+Commands:
+  dump       打印合并后生效的配置及每个 key 的来源，敏感项已脱敏
+  schema     根据各模块 Option 生成 JSON Schema
+  reference  根据各模块 Option 生成带注释的参考配置
+  push       发布本地配置文件到配置中心，先展示与当前内容的差异
+  delete     删除配置中心中的配置
+`
+
+// runConfigCommand 处理 config 子命令，返回进程退出码
+func runConfigCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, configUsage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "dump":
+		err = runConfigDump(args[1:])
+	case "schema":
+		err = runConfigGenerate("config schema", args[1:], config.JSONSchema)
+	case "reference":
+		err = runConfigGenerate("config reference", args[1:], func() ([]byte, error) {
+			return config.ReferenceConfig(), nil
+		})
+	case "push":
+		err = runConfigPush(args[1:])
+	case "delete":
+		err = runConfigDelete(args[1:])
+	case "-h", "--help", "help":
+		fmt.Fprint(os.Stdout, configUsage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unknown config command %q\n\n%s", args[0], configUsage)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+func runConfigDump(args []string) error {
+	fs := flag.NewFlagSet("config dump", flag.ExitOnError)
+	configFile := fs.String("c", "config.yaml", "config file")
+	format := fs.String("format", "table", "output format: table or json")
+	prefix := fs.String("prefix", "", "only print keys under this prefix, e.g. http")
+	_ = fs.Parse(args)
+
+	cm, err := config.NewConfigManager(config.NewOptions(*configFile))
+	if err != nil {
+		return err
+	}
+	defer cm.Close()
+
+	values := filterEffective(cm.Effective(), *prefix)
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]interface{}{
+			"sources": cm.Health(),
+			"values":  values,
+		})
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+		for _, v := range values {
+			fmt.Fprintf(w, "%s\t%v\t%s\n", v.Key, v.Value, v.Source)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unsupported format %q", *format)
+	}
+}
+
+func filterEffective(values []config.EffectiveValue, prefix string) []config.EffectiveValue {
+	prefix = strings.ToLower(strings.Trim(prefix, "."))
+	if prefix == "" {
+		return values
+	}
+	out := make([]config.EffectiveValue, 0, len(values))
+	for _, v := range values {
+		if v.Key == prefix || strings.HasPrefix(v.Key, prefix+".") {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func runConfigGenerate(name string, args []string, generate func() ([]byte, error)) error {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	output := fs.String("o", "", "output file, defaults to stdout")
+	_ = fs.Parse(args)
+
+	data, err := generate()
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*output, data, 0o644)
+}
+
+func runConfigPush(args []string) error {
+	fs := flag.NewFlagSet("config push", flag.ExitOnError)
+	configFile := fs.String("c", "config.yaml", "config file used to connect to the config center")
+	source := fs.String("source", "", "config source name, defaults to the first publishable source")
+	key := fs.String("key", "", "key to publish, e.g. nacos data id or dataId@group")
+	file := fs.String("f", "", "local file to publish, - for stdin")
+	dryRun := fs.Bool("dry-run", false, "only print the diff")
+	_ = fs.Parse(args)
+
+	if *key == "" || *file == "" {
+		return fmt.Errorf("-key and -f are required")
+	}
+	content, err := readInput(*file)
+	if err != nil {
+		return err
+	}
+
+	cm, err := config.NewConfigManager(config.NewOptions(*configFile))
+	if err != nil {
+		return err
+	}
+	defer cm.Close()
+
+	result, err := cm.PushConfig(*source, *key, content, *dryRun)
+	if err != nil {
+		return err
+	}
+	printPushResult(result, "published")
+	return nil
+}
+
+func runConfigDelete(args []string) error {
+	fs := flag.NewFlagSet("config delete", flag.ExitOnError)
+	configFile := fs.String("c", "config.yaml", "config file used to connect to the config center")
+	source := fs.String("source", "", "config source name, defaults to the first publishable source")
+	key := fs.String("key", "", "key to delete, e.g. nacos data id or dataId@group")
+	dryRun := fs.Bool("dry-run", false, "only print the keys to be removed")
+	_ = fs.Parse(args)
+
+	if *key == "" {
+		return fmt.Errorf("-key is required")
+	}
+
+	cm, err := config.NewConfigManager(config.NewOptions(*configFile))
+	if err != nil {
+		return err
+	}
+	defer cm.Close()
+
+	result, err := cm.DeleteConfig(*source, *key, *dryRun)
+	if err != nil {
+		return err
+	}
+	printPushResult(result, "deleted")
+	return nil
+}
+
+func readInput(path string) (string, error) {
+	if path == "-" {
+		b, err := io.ReadAll(os.Stdin)
+		return string(b), err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return string(b), nil
+}
+
+func printPushResult(result *config.PushResult, action string) {
+	fmt.Printf("%s %s:\n", result.Source, result.Key)
+	if len(result.Changes) == 0 {
+		fmt.Println("  (no changes)")
+	}
+	for _, c := range result.Changes {
+		switch c.Type {
+		case config.ChangeAdded:
+			fmt.Printf("  + %s: %v\n", c.Key, c.New)
+		case config.ChangeRemoved:
+			fmt.Printf("  - %s: %v\n", c.Key, c.Old)
+		default:
+			fmt.Printf("  ~ %s: %v -> %v\n", c.Key, c.Old, c.New)
+		}
+	}
+
+	switch {
+	case result.DryRun:
+		fmt.Println("dry run, nothing changed")
+	case result.Published:
+		fmt.Println(action)
+	default:
+		fmt.Println("content unchanged, nothing published")
+	}
+}
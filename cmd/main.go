@@ -23,6 +23,10 @@ func (s *stringFlag) Set(val string) error {
 
 func main() {
 
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:]))
+	}
+
 	configFlag := &stringFlag{value: "config.yaml"}
 	flag.Var(configFlag, "c", "config file")
 	flag.Parse()
@@ -6,8 +6,10 @@ package main
 
 import (
 	app "github.com/ahrtolia/goboot/pkg"
+	"github.com/ahrtolia/goboot/pkg/admin"
 	"github.com/ahrtolia/goboot/pkg/config"
 	"github.com/ahrtolia/goboot/pkg/cron_starter"
+	"github.com/ahrtolia/goboot/pkg/discovery"
 	"github.com/ahrtolia/goboot/pkg/gin_starter"
 	"github.com/ahrtolia/goboot/pkg/gorm_starter"
 	"github.com/ahrtolia/goboot/pkg/logger"
@@ -42,6 +44,14 @@ var (
 		redispkg.ProviderSet,
 	)
 
+	discoverySet = wire.NewSet(
+		discovery.ProviderSet,
+	)
+
+	adminSet = wire.NewSet(
+		admin.ProviderSet,
+	)
+
 	appSet = wire.NewSet(
 		app.ProviderSet,
 	)
@@ -53,6 +63,8 @@ var (
 		dbSet,
 		cronSet,
 		redisSet,
+		discoverySet,
+		adminSet,
 		appSet,
 	)
 )
@@ -8,8 +8,10 @@ package main
 
 import (
 	app "github.com/ahrtolia/goboot/pkg"
+	"github.com/ahrtolia/goboot/pkg/admin"
 	"github.com/ahrtolia/goboot/pkg/config"
 	"github.com/ahrtolia/goboot/pkg/cron_starter"
+	"github.com/ahrtolia/goboot/pkg/discovery"
 	"github.com/ahrtolia/goboot/pkg/gin_starter"
 	"github.com/ahrtolia/goboot/pkg/gorm_starter"
 	"github.com/ahrtolia/goboot/pkg/logger"
@@ -21,7 +23,10 @@ import (
 
 func CreateApp(configFile2 string) (*app.App, error) {
 	options := config.NewOptions(configFile2)
-	configManager := config.InitConfigManager(options)
+	configManager, err := config.InitConfigManager(options)
+	if err != nil {
+		return nil, err
+	}
 	zapLogger, err := logger.NewLogger(configManager)
 	if err != nil {
 		return nil, err
@@ -55,13 +60,31 @@ func CreateApp(configFile2 string) (*app.App, error) {
 	if err != nil {
 		return nil, err
 	}
-	context := app.NewContext(configManager, zapLogger, server, db, scheduler, redisClient)
+	discoveryOption, err := discovery.NewOption(configManager)
+	if err != nil {
+		return nil, err
+	}
+	registry, err := discovery.NewRegistry(zapLogger, discoveryOption)
+	if err != nil {
+		return nil, err
+	}
+	context := app.NewContext(configManager, zapLogger, server, db, scheduler, redisClient, registry)
 	loggerStarter := app.NewLoggerStarter(configManager, zapLogger)
 	httpStarter := app.NewHTTPStarter(configManager, server)
 	gormStarter := app.NewGormStarter(configManager, db)
 	cronStarter := app.NewCronStarter(configManager, scheduler)
 	redisStarter := app.NewRedisStarter(configManager, redisClient)
-	v := app.NewStarters(loggerStarter, httpStarter, gormStarter, cronStarter, redisStarter)
+	discoveryStarter := app.NewDiscoveryStarter(configManager, registry, server)
+	adminOption, err := admin.NewOption(configManager)
+	if err != nil {
+		return nil, err
+	}
+	adminAdmin, err := admin.NewAdmin(zapLogger, configManager, adminOption)
+	if err != nil {
+		return nil, err
+	}
+	adminStarter := app.NewAdminStarter(configManager, adminAdmin, server)
+	v := app.NewStarters(loggerStarter, httpStarter, gormStarter, cronStarter, redisStarter, discoveryStarter, adminStarter)
 	appApp, err := app.New(configManager, context, v)
 	if err != nil {
 		return nil, err
@@ -84,6 +107,10 @@ var (
 
 	redisSet = wire.NewSet(redispkg.ProviderSet)
 
+	discoverySet = wire.NewSet(discovery.ProviderSet)
+
+	adminSet = wire.NewSet(admin.ProviderSet)
+
 	appSet = wire.NewSet(app.ProviderSet)
 
 	globalSet = wire.NewSet(
@@ -93,6 +120,8 @@ var (
 		dbSet,
 		cronSet,
 		redisSet,
+		discoverySet,
+		adminSet,
 		appSet,
 	)
 )
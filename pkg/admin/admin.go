@@ -0,0 +1,138 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ahrtolia/goboot/pkg/config"
+	"github.com/gin-gonic/gin"
+	"github.com/google/wire"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type Option struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Token    string `mapstructure:"token"`     // Bearer token，为空时拒绝所有请求
+	BasePath string `mapstructure:"base_path"` // 管理接口路径前缀
+}
+
+func init() {
+	config.RegisterOption("admin", defaultOption())
+}
+
+func defaultOption() *Option {
+	return &Option{
+		BasePath: "/admin",
+	}
+}
+
+func NewOption(cfg *config.ConfigManager) (*Option, error) {
+	opt := defaultOption()
+	if adminCfg := cfg.GetViper().Sub("admin"); adminCfg != nil {
+		if err := adminCfg.Unmarshal(opt); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal admin options: %w", err)
+		}
+	}
+	return opt, nil
+}
+
+// Admin 管理 HTTP 管理接口，挂载在 gin_starter.Server 上并统一做 token 鉴权。
+// 其他模块可通过 Handle 注册自己的管理接口。
+type Admin struct {
+	mu       sync.RWMutex
+	logger   *zap.Logger
+	cfg      *config.ConfigManager
+	opt      *Option
+	handlers []func(r gin.IRouter)
+}
+
+func NewAdmin(logger *zap.Logger, cfg *config.ConfigManager, opt *Option) (*Admin, error) {
+	a := &Admin{
+		logger: logger.Named("admin"),
+		cfg:    cfg,
+		opt:    opt,
+	}
+	a.Handle(a.configRoutes)
+	a.Handle(a.logRoutes)
+
+	if err := cfg.RegisterReloader("admin", a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Handle 注册管理接口，路由相对于 base_path，已通过鉴权
+func (a *Admin) Handle(register func(r gin.IRouter)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.handlers = append(a.handlers, register)
+}
+
+// Mount 将所有管理接口挂载到 router
+func (a *Admin) Mount(router gin.IRouter) {
+	a.mu.RLock()
+	basePath := a.opt.BasePath
+	handlers := a.handlers
+	a.mu.RUnlock()
+
+	group := router.Group(basePath, a.auth)
+	for _, register := range handlers {
+		register(group)
+	}
+}
+
+func (a *Admin) Enabled() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.opt.Enabled
+}
+
+// auth 校验 Authorization: Bearer <token>，未启用或未配置 token 时拒绝访问
+func (a *Admin) auth(c *gin.Context) {
+	a.mu.RLock()
+	enabled := a.opt.Enabled
+	token := a.opt.Token
+	a.mu.RUnlock()
+
+	if !enabled {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	if token == "" {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin token not configured"})
+		return
+	}
+
+	// 只接受 "Bearer <token>"，不接受裸 token
+	got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	c.Next()
+}
+
+// ReloadConfig 更新 enabled / token，base_path 在路由重建时生效
+func (a *Admin) ReloadConfig(v *viper.Viper) error {
+	opt := defaultOption()
+	if adminCfg := v.Sub("admin"); adminCfg != nil {
+		if err := adminCfg.Unmarshal(opt); err != nil {
+			return fmt.Errorf("failed to unmarshal admin options: %w", err)
+		}
+	}
+
+	a.mu.Lock()
+	a.opt = opt
+	a.mu.Unlock()
+	return nil
+}
+
+// Wire Provider Set
+var ProviderSet = wire.NewSet(
+	NewOption,
+	NewAdmin,
+)
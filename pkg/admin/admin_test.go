@@ -0,0 +1,117 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ahrtolia/goboot/pkg/config"
+	"github.com/ahrtolia/goboot/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const testToken = "s3cret"
+
+// testConfig 启用管理接口与 logger.recent，不输出到控制台与文件
+const testConfig = `
+app:
+  name: admin-test
+logger:
+  level: info
+  console_enabled: false
+  file_enabled: false
+  recent:
+    enabled: true
+    per_level: 100
+admin:
+  enabled: true
+  token: ` + testToken + `
+`
+
+// newTestAdmin 按 content 写入本地配置文件并初始化 logger 与管理接口，返回配置文件路径与挂载了管理接口的路由
+func newTestAdmin(t *testing.T, content string) (string, *Admin, *gin.Engine) {
+	t.Helper()
+	local := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(local, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cm, err := config.NewConfigManager(config.Options{ConfigFile: config.ConfigFile(local)})
+	if err != nil {
+		t.Fatalf("new config manager: %v", err)
+	}
+	t.Cleanup(cm.Close)
+	if _, err := logger.NewLogger(cm); err != nil {
+		t.Fatalf("new logger: %v", err)
+	}
+	t.Cleanup(logger.Close)
+
+	opt, err := NewOption(cm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewAdmin(zap.NewNop(), cm, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	a.Mount(router)
+	return local, a, router
+}
+
+// serve 发送请求，authorization 为空时不带 Authorization 头
+func serve(router http.Handler, method, target, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuth(t *testing.T) {
+	_, a, router := newTestAdmin(t, testConfig)
+
+	cases := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{"missing token", "", http.StatusUnauthorized},
+		{"bare token", testToken, http.StatusUnauthorized},
+		{"wrong token", "Bearer wrong", http.StatusUnauthorized},
+		{"bearer token", "Bearer " + testToken, http.StatusOK},
+	}
+	for _, tc := range cases {
+		if w := serve(router, http.MethodGet, "/admin/log/level", tc.authorization); w.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, w.Code, tc.want)
+		}
+	}
+
+	reload := func(settings map[string]interface{}) {
+		v := viper.New()
+		v.Set("admin", settings)
+		if err := a.ReloadConfig(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 未配置 token 时拒绝所有请求
+	reload(map[string]interface{}{"enabled": true})
+	if w := serve(router, http.MethodGet, "/admin/log/level", "Bearer "); w.Code != http.StatusForbidden {
+		t.Errorf("without configured token: status = %d, want 403", w.Code)
+	}
+
+	// 未启用时不暴露管理接口
+	reload(map[string]interface{}{"enabled": false, "token": testToken})
+	if w := serve(router, http.MethodGet, "/admin/log/level", "Bearer "+testToken); w.Code != http.StatusNotFound {
+		t.Errorf("disabled: status = %d, want 404", w.Code)
+	}
+}
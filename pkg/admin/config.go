@@ -0,0 +1,107 @@
+package admin
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ahrtolia/goboot/pkg/config"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const maxConfigBody = 4 << 20
+
+func (a *Admin) configRoutes(r gin.IRouter) {
+	r.GET("/config", a.effectiveConfig)
+	r.PUT("/config/center", a.pushConfig)
+	r.DELETE("/config/center", a.deleteConfig)
+}
+
+// effectiveConfig 返回合并后生效的配置、每个 key 的来源以及各配置来源的状态，敏感项已脱敏
+// GET /admin/config?prefix=http
+func (a *Admin) effectiveConfig(c *gin.Context) {
+	values := a.cfg.Effective()
+	if prefix := strings.ToLower(strings.Trim(c.Query("prefix"), ".")); prefix != "" {
+		filtered := make([]config.EffectiveValue, 0, len(values))
+		for _, v := range values {
+			if v.Key == prefix || strings.HasPrefix(v.Key, prefix+".") {
+				filtered = append(filtered, v)
+			}
+		}
+		values = filtered
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sources": a.cfg.Health(),
+		"values":  values,
+	})
+}
+
+// pushConfig 将请求体发布到配置中心，请求体超过 maxConfigBody 时返回 413，不发布截断的内容
+// PUT /admin/config/center?source=nacos&key=goboot.yaml&dry_run=true
+func (a *Admin) pushConfig(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxConfigBody))
+	if err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := a.cfg.PushConfig(c.Query("source"), c.Query("key"), string(body), queryBool(c, "dry_run"))
+	if err != nil {
+		c.JSON(configErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if result.Published {
+		a.logger.Info("config published via admin",
+			zap.String("source", result.Source),
+			zap.String("key", result.Key),
+			zap.Int("changes", len(result.Changes)),
+			zap.String("client_ip", c.ClientIP()))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// deleteConfig 删除配置中心中的配置
+// DELETE /admin/config/center?source=nacos&key=goboot.yaml&dry_run=true
+func (a *Admin) deleteConfig(c *gin.Context) {
+	result, err := a.cfg.DeleteConfig(c.Query("source"), c.Query("key"), queryBool(c, "dry_run"))
+	if err != nil {
+		c.JSON(configErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if result.Published {
+		a.logger.Info("config deleted via admin",
+			zap.String("source", result.Source),
+			zap.String("key", result.Key),
+			zap.String("client_ip", c.ClientIP()))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func configErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, config.ErrConfigCenterNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, config.ErrPublishNotSupported):
+		return http.StatusNotImplemented
+	case errors.Is(err, config.ErrSourceNotConnected):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, config.ErrInvalidContent):
+		return http.StatusBadRequest
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+func queryBool(c *gin.Context, key string) bool {
+	b, _ := strconv.ParseBool(c.Query(key))
+	return b
+}
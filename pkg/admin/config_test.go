@@ -0,0 +1,21 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPushConfigTooLarge(t *testing.T) {
+	_, _, router := newTestAdmin(t, testConfig)
+
+	body := "app:\n  name: " + strings.Repeat("x", maxConfigBody) + "\n"
+	req := httptest.NewRequest(http.MethodPut, "/admin/config/center?source=http&key=app.yaml", strings.NewReader(body))
+	req.Header.Set("Authorization", bearer)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413, body = %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,167 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ahrtolia/goboot/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// tailHeartbeat 是实时查看在没有日志时发送心跳的间隔，避免被代理断开
+const tailHeartbeat = 15 * time.Second
+
+func (a *Admin) logRoutes(r gin.IRouter) {
+	r.GET("/log/level", a.logLevels)
+	r.PUT("/log/level", a.setLogLevel)
+	r.GET("/log/recent", a.recentLogs)
+	r.GET("/log/tail", a.tailLogs)
+}
+
+// logLevels 返回各模块当前生效的日志级别
+// GET /admin/log/level
+func (a *Admin) logLevels(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"levels": logger.Levels()})
+}
+
+// setLogLevel 调整模块日志级别，module 为空时调整全局级别；ttl 到期后恢复为配置的级别
+// PUT /admin/log/level?module=gorm&level=debug&ttl=10m
+func (a *Admin) setLogLevel(c *gin.Context) {
+	var ttl time.Duration
+	if raw := c.Query("ttl"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ttl: " + raw})
+			return
+		}
+		ttl = d
+	}
+
+	module := c.Query("module")
+	if err := logger.SetLevel(module, c.Query("level"), ttl); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, logger.ErrInvalidLevel) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	a.logger.Info("log level changed via admin",
+		zap.String("module", module),
+		zap.String("level", c.Query("level")),
+		zap.Duration("ttl", ttl),
+		zap.String("client_ip", c.ClientIP()))
+	c.JSON(http.StatusOK, gin.H{"levels": logger.Levels()})
+}
+
+// recentLogs 返回内存中最近的日志，需启用 logger.recent
+// GET /admin/log/recent?level=warn&module=redis&request_id=xxx&since=10m&until=2024-01-02T15:04:05Z&limit=200
+func (a *Admin) recentLogs(c *gin.Context) {
+	filter, err := parseLogFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.Limit == 0 {
+		filter.Limit = 500
+	}
+
+	records, err := logger.RecentLogs(filter)
+	if err != nil {
+		c.JSON(recentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"records": records})
+}
+
+// tailLogs 以 SSE 推送之后写入的满足条件的日志，每条为一个 log 事件；
+// logger.recent 被关闭时结束
+// GET /admin/log/tail?level=warn&module=redis&request_id=xxx
+func (a *Admin) tailLogs(c *gin.Context) {
+	filter, err := parseLogFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	records, cancel, err := logger.TailLogs(filter)
+	if err != nil {
+		c.JSON(recentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer cancel()
+
+	// 长连接不受 http.write_timeout 限制
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(tailHeartbeat)
+	defer heartbeat.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case rec, ok := <-records:
+			if !ok {
+				return false
+			}
+			c.SSEvent("log", rec)
+		case <-heartbeat.C:
+			_, _ = io.WriteString(w, ": heartbeat\n\n")
+		}
+		return true
+	})
+}
+
+// parseLogFilter 解析 level、module、request_id、since、until、limit。
+// since / until 为 RFC3339 时间，since 也可以是相对当前的时长，如 10m
+func parseLogFilter(c *gin.Context) (logger.RecentFilter, error) {
+	filter := logger.RecentFilter{
+		Level:     zapcore.DebugLevel,
+		Module:    c.Query("module"),
+		RequestID: c.Query("request_id"),
+	}
+	if raw := c.Query("level"); raw != "" {
+		if err := filter.Level.UnmarshalText([]byte(raw)); err != nil {
+			return filter, fmt.Errorf("invalid level: %s", raw)
+		}
+	}
+	if raw := c.Query("since"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			filter.Since = time.Now().Add(-d)
+		} else if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			filter.Since = t
+		} else {
+			return filter, fmt.Errorf("invalid since: %s", raw)
+		}
+	}
+	if raw := c.Query("until"); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, fmt.Errorf("invalid until: %s", raw)
+		}
+		filter.Until = t
+	}
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return filter, fmt.Errorf("invalid limit: %s", raw)
+		}
+		filter.Limit = n
+	}
+	return filter, nil
+}
+
+func recentErrorStatus(err error) int {
+	if errors.Is(err, logger.ErrRecentDisabled) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,166 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahrtolia/goboot/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const bearer = "Bearer " + testToken
+
+func TestLogLevel(t *testing.T) {
+	_, _, router := newTestAdmin(t, testConfig)
+
+	w := serve(router, http.MethodPut, "/admin/log/level?module=gorm&level=debug&ttl=10m", bearer)
+	if w.Code != http.StatusOK {
+		t.Fatalf("put status = %d, body = %s", w.Code, w.Body)
+	}
+
+	w = serve(router, http.MethodGet, "/admin/log/level", bearer)
+	var resp struct {
+		Levels []logger.ModuleLevel `json:"levels"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode %s: %v", w.Body, err)
+	}
+	got := map[string]logger.ModuleLevel{}
+	for _, l := range resp.Levels {
+		got[l.Module] = l
+	}
+	if got["gorm"].Level != "debug" || got["gorm"].Expires == nil {
+		t.Errorf("levels = %+v, want gorm at debug with expiry", resp.Levels)
+	}
+
+	for _, target := range []string{
+		"/admin/log/level?module=gorm",            // 缺少 level
+		"/admin/log/level?module=gorm&level=loud", // 无效 level
+		"/admin/log/level?module=gorm&level=debug&ttl=soon",
+	} {
+		if w := serve(router, http.MethodPut, target, bearer); w.Code != http.StatusBadRequest {
+			t.Errorf("PUT %s: status = %d, want 400", target, w.Code)
+		}
+	}
+}
+
+func TestRecentLogs(t *testing.T) {
+	_, _, router := newTestAdmin(t, testConfig)
+
+	logger.Named("http").With(zap.String("request_id", "req-1")).Info("handled")
+	logger.Named("redis").Warn("slow command")
+	logger.Named("redis.cluster").Error("connection refused")
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"handled", "slow command", "connection refused"}},
+		{"level=warn", []string{"slow command", "connection refused"}},
+		{"module=redis", []string{"slow command", "connection refused"}},
+		{"module=redis.cluster", []string{"connection refused"}},
+		{"request_id=req-1", []string{"handled"}},
+		{"limit=1", []string{"connection refused"}},
+		{"since=1h", []string{"handled", "slow command", "connection refused"}},
+		{"until=2000-01-01T00:00:00Z", nil},
+	}
+	for _, tc := range cases {
+		w := serve(router, http.MethodGet, "/admin/log/recent?"+tc.query, bearer)
+		var resp struct {
+			Records []logger.Record `json:"records"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Errorf("%q: decode %s: %v", tc.query, w.Body, err)
+			continue
+		}
+		var got []string
+		for _, rec := range resp.Records {
+			got = append(got, rec.Message)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%q: messages = %q, want %q", tc.query, got, tc.want)
+		}
+	}
+
+	for _, query := range []string{"level=loud", "since=yesterday", "until=10m", "limit=-1"} {
+		if w := serve(router, http.MethodGet, "/admin/log/recent?"+query, bearer); w.Code != http.StatusBadRequest {
+			t.Errorf("%q: status = %d, want 400", query, w.Code)
+		}
+	}
+}
+
+func TestTailLogs(t *testing.T) {
+	local, _, router := newTestAdmin(t, testConfig)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/admin/log/tail?module=redis", nil)
+	req.Header.Set("Authorization", bearer)
+
+	// 响应头在第一条事件写出时返回，订阅建立之前的日志不会推送，因此持续写日志直到收到响应
+	connected := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			logger.Named("http").Info("not tailed")
+			logger.Named("redis").Warn("slow command")
+			select {
+			case <-connected:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	resp, err := http.DefaultClient.Do(req)
+	close(connected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Errorf("content type = %q, want text/event-stream", ct)
+	}
+
+	r := bufio.NewReader(resp.Body)
+	event, _ := r.ReadString('\n')
+	data, _ := r.ReadString('\n')
+	blank, _ := r.ReadString('\n')
+	if event != "event:log\n" || !strings.HasPrefix(data, "data:") || blank != "\n" {
+		t.Fatalf("frame = %q %q %q, want event, data and a blank line", event, data, blank)
+	}
+	var rec logger.Record
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(data, "data:")), &rec); err != nil {
+		t.Fatalf("decode %q: %v", data, err)
+	}
+	if rec.Module != "redis" || rec.Message != "slow command" || rec.Level != "warn" {
+		t.Errorf("record = %+v", rec)
+	}
+
+	// 关闭 logger.recent 后实时查看随之结束
+	disabled := strings.Replace(testConfig, "enabled: true\n    per_level", "enabled: false\n    per_level", 1)
+	if err := os.WriteFile(local, []byte(disabled), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if ctx.Err() != nil {
+				t.Fatal("tail did not end after logger.recent was disabled")
+			}
+			break
+		}
+		if strings.HasPrefix(line, "data:") && !strings.Contains(line, `"module":"redis"`) {
+			t.Errorf("unexpected record on tail: %s", line)
+		}
+	}
+}
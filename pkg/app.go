@@ -11,6 +11,15 @@ import (
 	"syscall"
 )
 
+// appOption 是 app 节点，app.name 为服务名，服务发现等模块默认使用
+type appOption struct {
+	Name string `mapstructure:"name"`
+}
+
+func init() {
+	config.RegisterOption("app", appOption{Name: "goboot"})
+}
+
 type App struct {
 	Config   *config.ConfigManager
 	ctx      *Context
@@ -72,6 +81,9 @@ func (a *App) Stop(ctx context.Context) error {
 			zap.L().Warn("starter stop failed", zap.String("starter", s.Name()), zap.Error(err))
 		}
 	}
+	if a.Config != nil {
+		a.Config.Close()
+	}
 	return nil
 }
 
@@ -83,5 +95,7 @@ var ProviderSet = wire.NewSet(
 	NewGormStarter,
 	NewCronStarter,
 	NewRedisStarter,
+	NewDiscoveryStarter,
+	NewAdminStarter,
 	NewStarters,
 )
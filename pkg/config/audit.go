@@ -0,0 +1,256 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// auditOption 是 config.audit 节点
+type auditOption struct {
+	Webhook webhookOption `mapstructure:"webhook"`
+}
+
+type webhookOption struct {
+	URL     string            `mapstructure:"url"` // 为空时不推送
+	Timeout time.Duration     `mapstructure:"timeout"`
+	Token   string            `mapstructure:"token"` // Bearer token
+	Headers map[string]string `mapstructure:"headers"`
+}
+
+func defaultAuditOption() *auditOption {
+	return &auditOption{
+		Webhook: webhookOption{Timeout: 5 * time.Second},
+	}
+}
+
+func loadAuditOption(settings map[string]interface{}) (*auditOption, error) {
+	opt := defaultAuditOption()
+	v := viper.New()
+	for k, val := range settings {
+		v.Set(k, val)
+	}
+	if sub := v.Sub("config.audit"); sub != nil {
+		if err := sub.Unmarshal(opt); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config.audit options: %w", err)
+		}
+	}
+	return opt, nil
+}
+
+// ReloaderResult 是单个 ConfigReloader 的执行结果
+type ReloaderResult struct {
+	Name     string        `json:"name"`
+	Success  bool          `json:"success"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// AuditEvent 描述一次生效配置的变更，Changes 中的敏感值已脱敏
+type AuditEvent struct {
+	Time      time.Time        `json:"time"`
+	Source    string           `json:"source"`
+	Changes   []Change         `json:"changes"`
+	Reloaders []ReloaderResult `json:"reloaders"`
+}
+
+// Failed 表示是否有 reloader 执行失败
+func (e AuditEvent) Failed() bool {
+	for _, r := range e.Reloaders {
+		if !r.Success {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	configChangesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "goboot",
+		Subsystem: "config",
+		Name:      "changes_total",
+		Help:      "Number of effective config changes by source.",
+	}, []string{"source"})
+
+	configReloadsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "goboot",
+		Subsystem: "config",
+		Name:      "reloads_total",
+		Help:      "Number of config reloader invocations by reloader and result.",
+	}, []string{"reloader", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(configChangesTotal, configReloadsTotal)
+}
+
+type auditHooks struct {
+	mu    sync.RWMutex
+	hooks []func(AuditEvent)
+}
+
+func (h *auditHooks) add(fn func(AuditEvent)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hooks = append(h.hooks, fn)
+}
+
+func (h *auditHooks) list() []func(AuditEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]func(AuditEvent){}, h.hooks...)
+}
+
+// OnAudit 注册配置变更审计回调，回调在独立的 goroutine 中执行
+func (cm *ConfigManager) OnAudit(fn func(AuditEvent)) {
+	cm.auditHooks.add(fn)
+}
+
+// fireReload 计算与上次生效配置的差异，有变更时通知所有 reloader 并产生审计事件
+func (cm *ConfigManager) fireReload(source string) {
+	cm.mu.Lock()
+	currentConfig := cloneSettings(cm.v.AllSettings())
+	previous := cm.applied
+	cm.applied = currentConfig
+	reloaders := make(map[string]ConfigReloader, len(cm.reloaders))
+	for name, r := range cm.reloaders {
+		reloaders[name] = r
+	}
+	cm.mu.Unlock()
+
+	changes := DiffSettings(previous, currentConfig)
+	if len(changes) == 0 {
+		return
+	}
+
+	// strict 模式下重载不中断，仅告警
+	if section, _ := currentConfig["config"].(map[string]interface{}); cast.ToBool(section["strict"]) {
+		if unknown := unknownKeys(currentConfig); len(unknown) > 0 {
+			cm.logger.Warn("unknown config keys", zap.Stringers("keys", unknown))
+		}
+	}
+
+	go cm.dispatchReload(source, changes, currentConfig, reloaders)
+}
+
+func (cm *ConfigManager) dispatchReload(source string, changes []Change, currentConfig map[string]interface{}, reloaders map[string]ConfigReloader) {
+	names := make([]string, 0, len(reloaders))
+	for name := range reloaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]ReloaderResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, n string, r ConfigReloader) {
+			defer wg.Done()
+			newViper := viper.New()
+			for k, v := range cloneSettings(currentConfig) {
+				newViper.Set(k, v)
+			}
+
+			start := time.Now()
+			err := r.ReloadConfig(newViper)
+			results[i] = ReloaderResult{Name: n, Success: err == nil, Duration: time.Since(start)}
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i, name, reloaders[name])
+	}
+	wg.Wait()
+
+	cm.audit(AuditEvent{
+		Time:      time.Now(),
+		Source:    source,
+		Changes:   redactChanges(changes),
+		Reloaders: results,
+	}, currentConfig)
+}
+
+// audit 将审计事件输出到日志、Prometheus 指标、回调与 webhook
+func (cm *ConfigManager) audit(event AuditEvent, settings map[string]interface{}) {
+	configChangesTotal.WithLabelValues(event.Source).Inc()
+	for _, r := range event.Reloaders {
+		result := "success"
+		if !r.Success {
+			result = "failure"
+		}
+		configReloadsTotal.WithLabelValues(r.Name, result).Inc()
+	}
+
+	keys := make([]string, len(event.Changes))
+	for i, c := range event.Changes {
+		keys[i] = c.Key
+	}
+	fields := []zap.Field{
+		zap.String("source", event.Source),
+		zap.Strings("keys", keys),
+		zap.Any("changes", event.Changes),
+		zap.Any("reloaders", event.Reloaders),
+	}
+	if event.Failed() {
+		cm.logger.Warn("config changed, some reloaders failed", fields...)
+	} else {
+		cm.logger.Info("config changed", fields...)
+	}
+
+	for _, fn := range cm.auditHooks.list() {
+		go fn(event)
+	}
+
+	opt, err := loadAuditOption(settings)
+	if err != nil {
+		cm.logger.Warn("audit webhook skipped", zap.Error(err))
+		return
+	}
+	if opt.Webhook.URL != "" {
+		go sendAuditWebhook(cm.logger, opt.Webhook, event)
+	}
+}
+
+func sendAuditWebhook(logger *zap.Logger, opt webhookOption, event AuditEvent) {
+	logger = logger.With(zap.String("url", opt.URL))
+	body, err := json.Marshal(event)
+	if err != nil {
+		logger.Error("failed to marshal audit event", zap.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opt.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, opt.URL, bytes.NewReader(body))
+	if err != nil {
+		logger.Error("failed to build audit webhook request", zap.Error(err))
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range opt.Headers {
+		req.Header.Set(k, v)
+	}
+	if opt.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+opt.Token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		logger.Warn("failed to send audit webhook", zap.Error(err))
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		logger.Warn("audit webhook rejected event", zap.String("status", resp.Status))
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestAuditEvent(t *testing.T) {
+	webhook := make(chan AuditEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer hook-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var event AuditEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err == nil {
+			webhook <- event
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	remote := filepath.Join(dir, "remote")
+	if err := os.Mkdir(remote, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(remote, "app.yaml"), "redis:\n  addr: a:6379\n  password: old-secret\n")
+
+	local := filepath.Join(dir, "config.yaml")
+	writeFile(t, local, `config:
+  audit:
+    webhook:
+      url: `+srv.URL+`
+      token: hook-token
+config_center:
+  snapshot_dir: `+filepath.Join(dir, "snapshots")+`
+  sources:
+    - type: directory
+      name: remote
+      path: `+remote+`
+      poll_interval: 50ms
+`)
+
+	cm, err := NewConfigManager(NewOptions(local))
+	if err != nil {
+		t.Fatalf("new config manager: %v", err)
+	}
+	defer cm.Close()
+
+	_ = cm.RegisterReloader("ok", ConfigReloaderFunc(func(*viper.Viper) error { return nil }))
+	_ = cm.RegisterReloader("broken", ConfigReloaderFunc(func(*viper.Viper) error { return errors.New("boom") }))
+	events := make(chan AuditEvent, 1)
+	cm.OnAudit(func(e AuditEvent) { events <- e })
+
+	writeFile(t, filepath.Join(remote, "app.yaml"), "redis:\n  addr: b:6379\n  password: new-secret\n")
+
+	var event AuditEvent
+	select {
+	case event = <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for audit event")
+	}
+
+	if event.Source != "remote" {
+		t.Errorf("source = %q, want remote", event.Source)
+	}
+	want := []Change{
+		{Key: "redis.addr", Type: ChangeModified, Old: "a:6379", New: "b:6379"},
+		{Key: "redis.password", Type: ChangeModified, Old: RedactedValue, New: RedactedValue},
+	}
+	if len(event.Changes) != len(want) {
+		t.Fatalf("changes = %+v, want %+v", event.Changes, want)
+	}
+	for i, c := range event.Changes {
+		if c != want[i] {
+			t.Errorf("change[%d] = %+v, want %+v", i, c, want[i])
+		}
+	}
+
+	if len(event.Reloaders) != 2 || !event.Failed() {
+		t.Fatalf("reloaders = %+v, want 2 with a failure", event.Reloaders)
+	}
+	if r := event.Reloaders[0]; r.Name != "broken" || r.Success || r.Error != "boom" {
+		t.Errorf("reloader[0] = %+v, want failed broken", r)
+	}
+	if r := event.Reloaders[1]; r.Name != "ok" || !r.Success {
+		t.Errorf("reloader[1] = %+v, want successful ok", r)
+	}
+
+	select {
+	case got := <-webhook:
+		if len(got.Changes) != 2 || got.Changes[1].New != RedactedValue {
+			t.Errorf("webhook changes = %+v", got.Changes)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for audit webhook")
+	}
+}
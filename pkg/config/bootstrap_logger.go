@@ -0,0 +1,170 @@
+package config
+
+import (
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxBootstrapRecords 是 logger 初始化前最多缓存的记录数，超出后丢弃最早的记录
+const maxBootstrapRecords = 1000
+
+// LoggerAware 由需要使用 ConfigManager 日志的配置中心适配器实现，激活时注入带适配器名称的子 logger
+type LoggerAware interface {
+	SetLogger(logger *zap.Logger)
+}
+
+type bootstrapRecord struct {
+	entry  zapcore.Entry
+	fields []zapcore.Field
+}
+
+// bootstrapSink 是 ConfigManager 日志的落点。ConfigManager 先于 logger 创建，
+// 在 AttachLogger 之前记录只缓存不输出，接入后按原级别与字段重放到真正的 logger，
+// 避免 logger 含控制台输出时同一条记录打印两次。始终未接入时在 Close 时输出到控制台
+type bootstrapSink struct {
+	mu       sync.Mutex
+	fallback zapcore.Core
+	target   zapcore.Core
+	pending  []bootstrapRecord
+	dropped  int
+}
+
+func newBootstrapSink() *bootstrapSink {
+	encoderConfig := zap.NewDevelopmentEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	return &bootstrapSink{
+		fallback: zapcore.NewCore(
+			zapcore.NewConsoleEncoder(encoderConfig),
+			zapcore.Lock(os.Stdout),
+			zapcore.InfoLevel,
+		),
+	}
+}
+
+func (s *bootstrapSink) enabled(level zapcore.Level) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.target != nil {
+		return s.target.Enabled(level)
+	}
+	// 接入前无法确定最终级别，全部缓存
+	return true
+}
+
+func (s *bootstrapSink) write(ent zapcore.Entry, fields []zapcore.Field) error {
+	s.mu.Lock()
+	if target := s.target; target != nil {
+		s.mu.Unlock()
+		if ce := target.Check(ent, nil); ce != nil {
+			ce.Write(fields...)
+		}
+		return nil
+	}
+	defer s.mu.Unlock()
+
+	if len(s.pending) >= maxBootstrapRecords {
+		s.pending = s.pending[1:]
+		s.dropped++
+	}
+	s.pending = append(s.pending, bootstrapRecord{entry: ent, fields: fields})
+	return nil
+}
+
+func (s *bootstrapSink) sync() error {
+	s.mu.Lock()
+	core := s.fallback
+	if s.target != nil {
+		core = s.target
+	}
+	s.mu.Unlock()
+	return core.Sync()
+}
+
+// attach 切换到真正的 logger，并重放缓存的记录
+func (s *bootstrapSink) attach(target zapcore.Core) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replayLocked(target)
+	s.target = target
+}
+
+// flush 在始终未接入 logger 时将缓存的记录输出到控制台，例如启动失败或命令行工具退出时
+func (s *bootstrapSink) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.target == nil {
+		s.replayLocked(s.fallback)
+	}
+}
+
+func (s *bootstrapSink) replayLocked(core zapcore.Core) {
+	if s.dropped > 0 {
+		ent := zapcore.Entry{Level: zapcore.WarnLevel, Time: s.pending[0].entry.Time, LoggerName: "config",
+			Message: "bootstrap log buffer overflowed, earliest records dropped"}
+		_ = core.Write(ent, []zapcore.Field{zap.Int("dropped", s.dropped)})
+	}
+	for _, rec := range s.pending {
+		if ce := core.Check(rec.entry, nil); ce != nil {
+			ce.Write(rec.fields...)
+		}
+	}
+	_ = core.Sync()
+
+	s.pending = nil
+	s.dropped = 0
+}
+
+// bootstrapCore 是 ConfigManager 持有的 zapcore.Core，写入统一交给 bootstrapSink
+type bootstrapCore struct {
+	sink   *bootstrapSink
+	fields []zapcore.Field
+}
+
+func (c *bootstrapCore) Enabled(level zapcore.Level) bool {
+	return c.sink.enabled(level)
+}
+
+func (c *bootstrapCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &bootstrapCore{sink: c.sink, fields: merged}
+}
+
+func (c *bootstrapCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *bootstrapCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(all, c.fields...)
+	all = append(all, fields...)
+	return c.sink.write(ent, all)
+}
+
+func (c *bootstrapCore) Sync() error {
+	return c.sink.sync()
+}
+
+// Logger 返回 ConfigManager 及配置中心适配器使用的 logger。
+// logger 初始化（AttachLogger）之前的记录先缓存，初始化后重放
+func (cm *ConfigManager) Logger() *zap.Logger {
+	return cm.logger
+}
+
+// AttachLogger 将 ConfigManager 的日志接入真正的 logger，并按原级别与字段重放此前缓存的记录。
+// 重复调用会切换到新的 logger
+func (cm *ConfigManager) AttachLogger(l *zap.Logger) {
+	if l == nil {
+		return
+	}
+	cm.logSink.attach(l.Core())
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestBootstrapLoggerReplay(t *testing.T) {
+	sink := newBootstrapSink()
+	console, echoed := observer.New(zapcore.InfoLevel)
+	sink.fallback = console
+	cm := &ConfigManager{logSink: sink}
+	cm.logger = zap.New(&bootstrapCore{sink: sink}).Named("config")
+
+	cm.logger.Named("nacos").With(zap.String("source", "shared")).
+		Warn("config center unavailable", zap.Error(errors.New("timeout")))
+	cm.logger.Debug("initializing")
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	cm.AttachLogger(zap.New(core))
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("replayed %d records, want 1: %+v", len(entries), entries)
+	}
+	got := entries[0]
+	if got.Level != zapcore.WarnLevel || got.LoggerName != "config.nacos" || got.Message != "config center unavailable" {
+		t.Errorf("unexpected replayed entry: %+v", got.Entry)
+	}
+	fields := got.ContextMap()
+	if fields["source"] != "shared" || fields["error"] != "timeout" {
+		t.Errorf("replayed fields = %v", fields)
+	}
+
+	// 接入前只缓存不输出，避免 logger 含控制台输出时重复打印
+	if n := echoed.Len(); n != 0 {
+		t.Errorf("records echoed to console = %d, want 0", n)
+	}
+
+	// 接入后直接写入真正的 logger
+	cm.logger.Info("reconnected")
+	if n := logs.FilterMessage("reconnected").Len(); n != 1 {
+		t.Errorf("records after attach = %d, want 1", n)
+	}
+}
+
+func TestBootstrapLoggerFlush(t *testing.T) {
+	sink := newBootstrapSink()
+	console, echoed := observer.New(zapcore.InfoLevel)
+	sink.fallback = console
+	logger := zap.New(&bootstrapCore{sink: sink}).Named("config")
+
+	logger.Warn("failed to load local config")
+	logger.Debug("initializing")
+	if n := echoed.Len(); n != 0 {
+		t.Fatalf("records echoed before flush = %d, want 0", n)
+	}
+
+	// 未接入 logger 时 flush 输出到控制台，且只输出一次
+	sink.flush()
+	sink.flush()
+	if n := echoed.Len(); n != 1 {
+		t.Errorf("records flushed to console = %d, want 1", n)
+	}
+}
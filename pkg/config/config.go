@@ -2,11 +2,12 @@ package config
 
 import (
 	"errors"
-	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
+	"go.uber.org/zap"
 )
 
 var (
@@ -50,37 +51,77 @@ func NewOptions(configFile string) Options {
 }
 
 type ConfigManager struct {
-	options      Options
-	v            *viper.Viper
-	mu           sync.RWMutex
-	reloaders    map[string]ConfigReloader
-	configCenter ConfigCenter
-	adapters     map[string]ConfigCenter
+	options       Options
+	v             *viper.Viper
+	mu            sync.RWMutex
+	reloaders     map[string]ConfigReloader
+	sources       []*configSource
+	adapters      map[string]ConfigCenter
+	factories     map[string]func() ConfigCenter
+	localSettings map[string]interface{}
+	localLayers   []SourceLayer // 本地配置文件与 profile 配置文件
+	envLayers     []SourceLayer // 环境变量覆盖的 key，每个变量一层
+	centerOpt     *centerOption
+	health        *healthRegistry
+	stopOnce      sync.Once
+	stopCh        chan struct{}
+	applied       map[string]interface{} // 最近一次通知 reloader 的配置，用于计算变更
+	auditHooks    auditHooks
+	logger        *zap.Logger
+	logSink       *bootstrapSink
 }
 
-func NewConfigManager(opt Options) *ConfigManager {
+func NewConfigManager(opt Options) (*ConfigManager, error) {
 
 	cm := &ConfigManager{
 		options:   opt,
 		v:         viper.New(),
 		reloaders: make(map[string]ConfigReloader),
 		adapters:  make(map[string]ConfigCenter),
+		factories: make(map[string]func() ConfigCenter),
+		health:    newHealthRegistry(),
+		stopCh:    make(chan struct{}),
+		logSink:   newBootstrapSink(),
 	}
+	cm.logger = zap.New(&bootstrapCore{sink: cm.logSink}, zap.AddCaller()).Named("config")
 
-	// 注册 Nacos 适配器
-	cm.RegisterAdapter(NewNacosAdapter())
+	// 注册内置适配器
+	cm.RegisterAdapterFactory("nacos", NewNacosAdapter)
+	cm.RegisterAdapterFactory("etcd", NewEtcdAdapter)
+	cm.RegisterAdapterFactory("consul", NewConsulAdapter)
+	cm.RegisterAdapterFactory("directory", NewDirectoryAdapter)
+	cm.RegisterAdapterFactory("http", NewHTTPAdapter)
 
 	localConfigErr := cm.initLocal(string(opt.ConfigFile)) // 从本地文件加载
 	if localConfigErr != nil {
-		fmt.Println(localConfigErr)
+		cm.logger.Warn("failed to init local config", zap.Error(localConfigErr))
 	}
 
-	configCenterErr := cm.initConfigCenter() // 激活 Nacos 并 merge 配置
-	if configCenterErr != nil {
-		fmt.Println(configCenterErr)
+	// 叠加 profile 与环境变量后再解析配置来源，config_center 参数同样可以被覆盖
+	cm.mu.Lock()
+	cm.setLocalLayersLocked(cm.loadLocalLayers(string(opt.ConfigFile), cloneSettings(cm.v.AllSettings())))
+	cm.composeLocked()
+	cm.mu.Unlock()
+
+	// 激活配置来源并 merge 配置，失败时按 fail_policy 处理
+	if err := cm.initConfigCenter(); err != nil {
+		cm.logSink.flush()
+		return nil, err
+	}
+
+	// strict 模式下存在未知 key 时启动失败
+	if err := cm.checkStrict(); err != nil {
+		cm.Close()
+		return nil, err
+	}
+
+	cm.mu.Lock()
+	if cm.applied == nil {
+		cm.applied = cloneSettings(cm.v.AllSettings())
 	}
+	cm.mu.Unlock()
 
-	return cm
+	return cm, nil
 }
 
 func (cm *ConfigManager) initLocal(configFile string) error {
@@ -89,61 +130,168 @@ func (cm *ConfigManager) initLocal(configFile string) error {
 	err := cm.v.ReadInConfig()
 	if err != nil {
 		// 改成 warn 模式，允许 fallback 到远程配置
-		fmt.Println("[Config] Failed to load local config:", err)
+		cm.logger.Warn("failed to load local config", zap.String("file", configFile), zap.Error(err))
 	}
 
 	cm.v.WatchConfig()
 	cm.v.OnConfigChange(func(e fsnotify.Event) {
-		cm.fireReload()
+		cm.reloadLocal(configFile)
+		cm.fireReload(SourceFile + ":" + configFile)
 	})
 
 	return nil
 }
 
+// reloadLocal 重新读取本地配置文件及 profile 配置文件，并与各配置来源重新合并
+func (cm *ConfigManager) reloadLocal(configFile string) {
+	local := viper.New()
+	local.SetConfigFile(configFile)
+	if err := local.ReadInConfig(); err != nil {
+		cm.logger.Error("failed to reload local config", zap.String("file", configFile), zap.Error(err))
+		return
+	}
+	layers := cm.loadLocalLayers(configFile, cloneSettings(local.AllSettings()))
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.setLocalLayersLocked(layers)
+	cm.composeLocked()
+}
+
+func (cm *ConfigManager) setLocalLayersLocked(layers []SourceLayer) {
+	cm.localLayers = layers
+	cm.localSettings = layersSettings(layers)
+}
+
 func (cm *ConfigManager) initConfigCenter() error {
-	if cm.options.ConfigCenter != "" {
-		centerConfig := cm.v.Sub(string("config_center." + cm.options.ConfigCenter))
-		if centerConfig == nil {
-			return nil
-		}
-		return cm.ActivateConfigCenter(string(cm.options.ConfigCenter))
+	centerOpt, err := loadCenterOption(cm.v)
+	if err != nil {
+		return err
 	}
+	cm.centerOpt = centerOpt
 
-	if cm.v.Sub("config_center.nacos") != nil {
-		return cm.ActivateConfigCenter("nacos")
+	sources, err := cm.buildSources(centerOpt)
+	if err != nil {
+		return err
+	}
+
+	cm.mu.Lock()
+	cm.sources = sources
+	cm.mu.Unlock()
+
+	// 各来源独立初始化，单个来源不可用时按其 fail_policy 处理
+	for _, src := range sources {
+		if err = cm.activateSource(src); err != nil {
+			if err = cm.handleSourceFailure(src, err); err != nil {
+				cm.Close()
+				return err
+			}
+		}
 	}
+
+	cm.mu.Lock()
+	cm.composeLocked()
+	cm.mu.Unlock()
 	return nil
 }
 
+// centerName 优先使用 Options 指定的配置中心，其配置块不存在时按名称顺序选择第一个已配置的适配器
+func (cm *ConfigManager) centerName() string {
+	if cm.options.ConfigCenter != "" && cm.v.Sub(string("config_center."+cm.options.ConfigCenter)) != nil {
+		return string(cm.options.ConfigCenter)
+	}
+
+	for _, name := range cm.adapterNames() {
+		if cm.v.Sub("config_center."+name) != nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// adapterNames 返回所有可用的配置中心类型，按名称排序
+func (cm *ConfigManager) adapterNames() []string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	seen := make(map[string]bool, len(cm.adapters)+len(cm.factories))
+	names := make([]string, 0, len(cm.adapters)+len(cm.factories))
+	for name := range cm.adapters {
+		seen[name] = true
+		names = append(names, name)
+	}
+	for name := range cm.factories {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisterAdapter 注册一个配置中心实例，同类型只能作为一个来源启用
 func (cm *ConfigManager) RegisterAdapter(adapter ConfigCenter) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 	cm.adapters[adapter.Name()] = adapter
 }
 
-func (cm *ConfigManager) ActivateConfigCenter(name string) error {
+// RegisterAdapterFactory 注册配置中心工厂，每个来源使用独立的实例，同类型可声明多个来源
+func (cm *ConfigManager) RegisterAdapterFactory(name string, factory func() ConfigCenter) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
+	cm.factories[name] = factory
+}
 
-	adapter, exists := cm.adapters[name]
-	if !exists {
-		return ErrConfigCenterNotFound
+// ActivateConfigCenter 切换为仅使用指定的配置中心
+func (cm *ConfigManager) ActivateConfigCenter(name string) error {
+	if _, err := cm.newAdapter(name); err != nil {
+		return err
 	}
 
-	if cm.configCenter != nil {
-		cm.configCenter.Close()
-	}
+	cm.mu.Lock()
+	cm.closeSourcesLocked()
+	src := cm.legacySource(name)
+	cm.sources = []*configSource{src}
+	cm.mu.Unlock()
 
-	if err := adapter.Init(cm.v); err != nil {
-		return fmt.Errorf("failed to init config center: %w", err)
+	if err := cm.activateSource(src); err != nil {
+		return err
 	}
 
-	if err := adapter.Watch(cm.v, cm.fireReload); err != nil {
-		return fmt.Errorf("failed to watch config center: %w", err)
+	cm.mu.Lock()
+	cm.composeLocked()
+	cm.mu.Unlock()
+	return nil
+}
+
+// Health 返回各配置来源的状态
+func (cm *ConfigManager) Health() []SourceHealth {
+	return cm.health.list()
+}
+
+// Stale 表示当前是否有配置来源未连接（正在使用快照或本地配置）
+func (cm *ConfigManager) Stale() bool {
+	for _, s := range cm.health.list() {
+		if s.Status != SourceStatusUp {
+			return true
+		}
 	}
+	return false
+}
 
-	cm.configCenter = adapter
-	return nil
+// Close 停止后台重连并关闭所有配置来源
+func (cm *ConfigManager) Close() {
+	cm.stopOnce.Do(func() {
+		close(cm.stopCh)
+	})
+
+	cm.mu.Lock()
+	cm.closeSourcesLocked()
+	cm.mu.Unlock()
+
+	// 未接入 logger 时（启动失败、命令行工具）输出缓存的日志
+	cm.logSink.flush()
 }
 
 func (cm *ConfigManager) RegisterReloader(name string, reloader ConfigReloader) error {
@@ -158,26 +306,6 @@ func (cm *ConfigManager) RegisterReloader(name string, reloader ConfigReloader)
 	return nil
 }
 
-func (cm *ConfigManager) fireReload() {
-	cm.mu.RLock()
-	currentConfig := cloneSettings(cm.v.AllSettings())
-	cm.mu.RUnlock()
-
-	for name, reloader := range cm.reloaders {
-		go func(n string, r ConfigReloader) {
-			newViper := viper.New()
-			for k, v := range currentConfig {
-				newViper.Set(k, v)
-			}
-			if err := r.ReloadConfig(newViper); err != nil {
-				fmt.Printf("[Config] Reloader [%s] failed: %v\n", n, err)
-			} else {
-				fmt.Printf("[Config] Reloader [%s] success\n", n)
-			}
-		}(name, reloader)
-	}
-}
-
 func (cm *ConfigManager) ReloadConfig(newViper *viper.Viper) error {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
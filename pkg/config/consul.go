@@ -0,0 +1,273 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type consulOption struct {
+	Address       string        `mapstructure:"address"`
+	Scheme        string        `mapstructure:"scheme"`
+	Token         string        `mapstructure:"token"`      // ACL token
+	Datacenter    string        `mapstructure:"datacenter"` // 为空时使用 agent 所在数据中心
+	WaitTime      time.Duration `mapstructure:"wait_time"`  // blocking query 最长等待时间
+	RetryInterval time.Duration `mapstructure:"retry_interval"`
+	TLS           tlsOption     `mapstructure:"tls"`
+	Keys          []kvKeyOption `mapstructure:"keys"` // 按声明顺序合并
+}
+
+type consulEntry struct {
+	kvEntry
+	index uint64
+}
+
+type consulAdapter struct {
+	mu      sync.Mutex
+	client  *consulapi.Client
+	opt     *consulOption
+	base    map[string]interface{}
+	entries []*consulEntry
+	cancel  context.CancelFunc
+	logger  *zap.Logger
+}
+
+func NewConsulAdapter() ConfigCenter {
+	return &consulAdapter{logger: zap.NewNop()}
+}
+
+func (c *consulAdapter) Name() string {
+	return "consul"
+}
+
+func (c *consulAdapter) SetLogger(logger *zap.Logger) {
+	c.logger = logger
+}
+
+func defaultConsulOption() *consulOption {
+	return &consulOption{
+		Address:       "127.0.0.1:8500",
+		Scheme:        "http",
+		WaitTime:      5 * time.Minute,
+		RetryInterval: 3 * time.Second,
+	}
+}
+
+func loadConsulOption(v *viper.Viper) (*consulOption, error) {
+	sub := v.Sub("config_center.consul")
+	if sub == nil {
+		return nil, fmt.Errorf("missing consul config block in viper")
+	}
+	opt := defaultConsulOption()
+	if err := sub.Unmarshal(opt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal consul options: %w", err)
+	}
+	if len(opt.Keys) == 0 {
+		return nil, fmt.Errorf("no consul keys configured")
+	}
+	return opt, nil
+}
+
+func (c *consulAdapter) Init(v *viper.Viper) error {
+	opt, err := loadConsulOption(v)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]*consulEntry, 0, len(opt.Keys))
+	for _, item := range opt.Keys {
+		entry, err := newKVEntry(item)
+		if err != nil {
+			return fmt.Errorf("consul: %w", err)
+		}
+		entries = append(entries, &consulEntry{kvEntry: *entry})
+	}
+
+	clientCfg := consulapi.DefaultConfig()
+	clientCfg.Address = opt.Address
+	clientCfg.Scheme = opt.Scheme
+	clientCfg.Token = opt.Token
+	clientCfg.Datacenter = opt.Datacenter
+	if opt.TLS.Enabled {
+		clientCfg.Scheme = "https"
+		clientCfg.TLSConfig = consulapi.TLSConfig{
+			Address:            opt.TLS.ServerName,
+			CAFile:             opt.TLS.CAFile,
+			CertFile:           opt.TLS.CertFile,
+			KeyFile:            opt.TLS.KeyFile,
+			InsecureSkipVerify: opt.TLS.InsecureSkipVerify,
+		}
+	}
+
+	client, err := consulapi.NewClient(clientCfg)
+	if err != nil {
+		return fmt.Errorf("failed to create consul client: %w", err)
+	}
+
+	for _, entry := range entries {
+		c.logger.Info("initializing", zap.Stringer("key", entry), zap.Bool("tree", entry.tree), zap.String("datacenter", opt.Datacenter))
+		if _, err = c.fetch(context.Background(), client, opt, entry, 0); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.client = client
+	c.opt = opt
+	c.base = cloneSettings(v.AllSettings())
+	c.entries = entries
+	c.applyLocked(v)
+
+	return nil
+}
+
+// fetch 读取 entry，waitIndex > 0 时为 blocking query；返回值表示内容是否更新
+func (c *consulAdapter) fetch(ctx context.Context, client *consulapi.Client, opt *consulOption, entry *consulEntry, waitIndex uint64) (bool, error) {
+	q := (&consulapi.QueryOptions{
+		WaitIndex: waitIndex,
+		WaitTime:  opt.WaitTime,
+	}).WithContext(ctx)
+
+	values := make(map[string][]byte)
+	var meta *consulapi.QueryMeta
+	var err error
+	if entry.tree {
+		var pairs consulapi.KVPairs
+		pairs, meta, err = client.KV().List(entry.key, q)
+		for _, pair := range pairs {
+			values[pair.Key] = pair.Value
+		}
+	} else {
+		var pair *consulapi.KVPair
+		pair, meta, err = client.KV().Get(entry.key, q)
+		if pair != nil {
+			values[pair.Key] = pair.Value
+		}
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get config %s from consul: %w", entry, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// index 未变化说明等待超时
+	if waitIndex > 0 && meta.LastIndex == waitIndex {
+		return false, nil
+	}
+	entry.index = nextConsulIndex(waitIndex, meta.LastIndex)
+
+	entry.values = values
+	if err = entry.rebuild(); err != nil {
+		return false, fmt.Errorf("failed to parse consul config %s: %w", entry, err)
+	}
+	return true, nil
+}
+
+// nextConsulIndex 按 Consul blocking query 的约定计算下一次等待的 index：
+// index 回退（快照恢复、leader 切换等）时重置为 0，否则至少为 1，避免之后的请求一直等到 wait_time
+func nextConsulIndex(waitIndex, lastIndex uint64) uint64 {
+	if lastIndex < waitIndex {
+		return 0
+	}
+	if lastIndex < 1 {
+		return 1
+	}
+	return lastIndex
+}
+
+func (c *consulAdapter) Watch(v *viper.Viper, onChange func()) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client == nil {
+		return fmt.Errorf("consul client not initialized")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	for _, entry := range c.entries {
+		if !entry.refresh {
+			continue
+		}
+		go c.watchEntry(ctx, c.client, c.opt, v, entry, onChange)
+	}
+	return nil
+}
+
+// watchEntry 基于 index 的长轮询监听 key 变更
+func (c *consulAdapter) watchEntry(ctx context.Context, client *consulapi.Client, opt *consulOption, v *viper.Viper, entry *consulEntry, onChange func()) {
+	for {
+		c.mu.Lock()
+		index := entry.index
+		c.mu.Unlock()
+		// 按 Consul 约定 index 必须大于 0，否则 blocking query 会立即返回
+		if index == 0 {
+			index = 1
+		}
+
+		changed, err := c.fetch(ctx, client, opt, entry, index)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			c.logger.Warn("watch failed", zap.Stringer("key", entry), zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(opt.RetryInterval):
+			}
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		c.mu.Lock()
+		c.applyLocked(v)
+		c.mu.Unlock()
+
+		c.logger.Info("config changed, reloading", zap.Stringer("key", entry))
+		onChange()
+	}
+}
+
+func (c *consulAdapter) applyLocked(v *viper.Viper) {
+	merged := cloneSettings(c.base)
+	for _, entry := range c.entries {
+		merged = deepMerge(merged, entry.settings)
+	}
+	replaceSettings(v, merged)
+}
+
+func (c *consulAdapter) Layers() []SourceLayer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	layers := make([]SourceLayer, 0, len(c.entries))
+	for _, entry := range c.entries {
+		layers = append(layers, SourceLayer{
+			Name:     "consul:" + entry.String(),
+			Settings: cloneSettings(entry.settings),
+		})
+	}
+	return layers
+}
+
+func (c *consulAdapter) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+}
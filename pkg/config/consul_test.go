@@ -0,0 +1,168 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// fakeConsulKV 是 Consul KV HTTP API 的最小实现，支持 recurse 与 blocking query
+type fakeConsulKV struct {
+	mu      sync.Mutex
+	index   uint64
+	data    map[string]string
+	changed chan struct{}
+	token   string
+	dc      string
+}
+
+func newFakeConsulKV(token, dc string) *fakeConsulKV {
+	return &fakeConsulKV{
+		index:   1,
+		data:    make(map[string]string),
+		changed: make(chan struct{}),
+		token:   token,
+		dc:      dc,
+	}
+}
+
+func (f *fakeConsulKV) put(key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = value
+	f.index++
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeConsulKV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Consul-Token") != f.token {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if r.URL.Query().Get("dc") != f.dc {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	_, recurse := r.URL.Query()["recurse"]
+	waitIndex, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+
+	f.mu.Lock()
+	if waitIndex > 0 && waitIndex >= f.index {
+		changed := f.changed
+		f.mu.Unlock()
+		select {
+		case <-changed:
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+			return
+		}
+		f.mu.Lock()
+	}
+	defer f.mu.Unlock()
+
+	type pair struct {
+		Key         string
+		Value       []byte
+		ModifyIndex uint64
+	}
+	pairs := make([]pair, 0)
+	for k, v := range f.data {
+		if k == key || (recurse && strings.HasPrefix(k, key)) {
+			pairs = append(pairs, pair{Key: k, Value: []byte(v), ModifyIndex: f.index})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Key < pairs[j].Key })
+
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(f.index, 10))
+	if len(pairs) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(pairs)
+}
+
+func TestConsulAdapter(t *testing.T) {
+	kv := newFakeConsulKV("secret-token", "dc2")
+	kv.put("goboot/config.json", `{"http": {"port": 9090}}`)
+	kv.put("goboot/flags/checkout/enabled", "false")
+
+	server := httptest.NewServer(kv)
+	defer server.Close()
+
+	v := viper.New()
+	v.Set("config_center", map[string]interface{}{
+		"consul": map[string]interface{}{
+			"address":    strings.TrimPrefix(server.URL, "http://"),
+			"token":      "secret-token",
+			"datacenter": "dc2",
+			"wait_time":  "1s",
+			"keys": []map[string]interface{}{
+				{"key": "goboot/config.json"},
+				{"key": "goboot/flags/", "tree": true, "prefix": "feature"},
+			},
+		},
+	})
+
+	adapter := NewConsulAdapter()
+	if err := adapter.Init(v); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer adapter.Close()
+
+	if got := v.GetInt("http.port"); got != 9090 {
+		t.Errorf("http.port = %d, want 9090", got)
+	}
+	if v.GetBool("feature.checkout.enabled") {
+		t.Errorf("feature.checkout.enabled = true, want false")
+	}
+
+	changed := make(chan struct{}, 10)
+	if err := adapter.Watch(v, func() { changed <- struct{}{} }); err != nil {
+		t.Fatalf("watch failed: %v", err)
+	}
+
+	// fakeConsulKV 的 index 是全局的，一次写入会唤醒两个 key 的监听，
+	// 等待两次通知后 v 不再被后台写入，此时读取不会与 watchEntry 竞争
+	kv.put("goboot/flags/checkout/enabled", "true")
+	deadline := time.After(5 * time.Second)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-changed:
+		case <-deadline:
+			t.Fatal("feature.checkout.enabled not updated")
+		}
+	}
+	if !v.GetBool("feature.checkout.enabled") {
+		t.Error("feature.checkout.enabled = false, want true")
+	}
+}
+
+func TestNextConsulIndex(t *testing.T) {
+	cases := []struct {
+		wait, last, want uint64
+	}{
+		{0, 0, 1}, // 首次读取返回 0 时至少为 1
+		{0, 7, 7}, // 首次读取
+		{7, 9, 9}, // 正常更新
+		{9, 3, 0}, // 快照恢复后 index 回退，重新读取
+		{1, 0, 0}, // 回退到 0
+		{5, 5, 5}, // 等待超时
+	}
+	for _, tc := range cases {
+		if got := nextConsulIndex(tc.wait, tc.last); got != tc.want {
+			t.Errorf("nextConsulIndex(%d, %d) = %d, want %d", tc.wait, tc.last, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const (
+	DirectoryModeMerge = "merge" // 每个文件按格式解析后依文件名顺序合并
+	DirectoryModeFiles = "files" // 文件名作为 key，文件内容作为值（适用于 Secret）
+)
+
+type directoryOption struct {
+	Path         string        `mapstructure:"path"`
+	Mode         string        `mapstructure:"mode"`
+	Prefix       string        `mapstructure:"prefix"`
+	PollInterval time.Duration `mapstructure:"poll_interval"` // 兜底轮询间隔，0 表示仅依赖文件事件
+}
+
+// directoryAdapter 从目录加载配置，适用于 Kubernetes 挂载的 ConfigMap / Secret。
+// Kubernetes 通过原子替换 ..data 软链接更新挂载内容，这里监听目录本身并比较内容指纹，
+// 不依赖单个文件的写事件。
+type directoryAdapter struct {
+	mu          sync.Mutex
+	opt         *directoryOption
+	base        map[string]interface{}
+	settings    map[string]interface{}
+	fingerprint string
+	watcher     *fsnotify.Watcher
+	stopCh      chan struct{}
+	logger      *zap.Logger
+}
+
+func NewDirectoryAdapter() ConfigCenter {
+	return &directoryAdapter{logger: zap.NewNop()}
+}
+
+func (d *directoryAdapter) Name() string {
+	return "directory"
+}
+
+func (d *directoryAdapter) SetLogger(logger *zap.Logger) {
+	d.logger = logger
+}
+
+func defaultDirectoryOption() *directoryOption {
+	return &directoryOption{
+		Mode:         DirectoryModeMerge,
+		PollInterval: 10 * time.Second,
+	}
+}
+
+func loadDirectoryOption(v *viper.Viper) (*directoryOption, error) {
+	sub := v.Sub("config_center.directory")
+	if sub == nil {
+		return nil, fmt.Errorf("missing directory config block in viper")
+	}
+	opt := defaultDirectoryOption()
+	if err := sub.Unmarshal(opt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal directory options: %w", err)
+	}
+	if opt.Path == "" {
+		return nil, fmt.Errorf("no directory path configured")
+	}
+	if opt.Mode != DirectoryModeMerge && opt.Mode != DirectoryModeFiles {
+		return nil, fmt.Errorf("invalid directory mode: %s", opt.Mode)
+	}
+	return opt, nil
+}
+
+func (d *directoryAdapter) Init(v *viper.Viper) error {
+	opt, err := loadDirectoryOption(v)
+	if err != nil {
+		return err
+	}
+
+	d.logger.Info("initializing", zap.String("path", opt.Path), zap.String("mode", opt.Mode))
+
+	settings, fingerprint, err := readDirectory(opt)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.opt = opt
+	d.base = cloneSettings(v.AllSettings())
+	d.settings = settings
+	d.fingerprint = fingerprint
+	d.applyLocked(v)
+
+	return nil
+}
+
+func (d *directoryAdapter) Watch(v *viper.Viper, onChange func()) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.opt == nil {
+		return fmt.Errorf("directory source not initialized")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create directory watcher: %w", err)
+	}
+	if err = watcher.Add(d.opt.Path); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("failed to watch directory %s: %w", d.opt.Path, err)
+	}
+
+	d.watcher = watcher
+	d.stopCh = make(chan struct{})
+	go d.loop(v, watcher, d.stopCh, onChange)
+	return nil
+}
+
+func (d *directoryAdapter) loop(v *viper.Viper, watcher *fsnotify.Watcher, stopCh chan struct{}, onChange func()) {
+	var tick <-chan time.Time
+	if d.opt.PollInterval > 0 {
+		ticker := time.NewTicker(d.opt.PollInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	// 合并短时间内的多次事件，软链接替换通常会产生一组 create/rename/remove
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-stopCh:
+			return
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			debounce = time.After(100 * time.Millisecond)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			d.logger.Warn("watch error", zap.String("path", d.opt.Path), zap.Error(err))
+		case <-debounce:
+			debounce = nil
+			d.reload(v, onChange)
+		case <-tick:
+			d.reload(v, onChange)
+		}
+	}
+}
+
+// reload 内容指纹变化时重新加载
+func (d *directoryAdapter) reload(v *viper.Viper, onChange func()) {
+	settings, fingerprint, err := readDirectory(d.opt)
+	if err != nil {
+		d.logger.Error("failed to load config", zap.String("path", d.opt.Path), zap.Error(err))
+		return
+	}
+
+	d.mu.Lock()
+	if fingerprint == d.fingerprint {
+		d.mu.Unlock()
+		return
+	}
+	d.settings = settings
+	d.fingerprint = fingerprint
+	d.applyLocked(v)
+	d.mu.Unlock()
+
+	d.logger.Info("config changed, reloading", zap.String("path", d.opt.Path))
+	onChange()
+}
+
+func (d *directoryAdapter) applyLocked(v *viper.Viper) {
+	replaceSettings(v, deepMerge(cloneSettings(d.base), d.settings))
+}
+
+func (d *directoryAdapter) Layers() []SourceLayer {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.opt == nil {
+		return nil
+	}
+	return []SourceLayer{{
+		Name:     "directory:" + d.opt.Path,
+		Settings: cloneSettings(d.settings),
+	}}
+}
+
+func (d *directoryAdapter) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stopCh != nil {
+		close(d.stopCh)
+		d.stopCh = nil
+	}
+	if d.watcher != nil {
+		_ = d.watcher.Close()
+		d.watcher = nil
+	}
+}
+
+// readDirectory 读取目录下的所有配置文件（跳过 Kubernetes 的 ..data 等内部条目与隐藏文件），
+// 返回合并后的配置与内容指纹
+func readDirectory(opt *directoryOption) (map[string]interface{}, string, error) {
+	dirEntries, err := os.ReadDir(opt.Path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read config directory: %w", err)
+	}
+
+	names := make([]string, 0, len(dirEntries))
+	for _, entry := range dirEntries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	settings := make(map[string]interface{})
+	for _, name := range names {
+		path := filepath.Join(opt.Path, name)
+		// os.Stat 跟随软链接，Kubernetes 挂载的文件均为指向 ..data 的软链接
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+		hash.Write([]byte(name))
+		hash.Write([]byte{0})
+		hash.Write(content)
+		hash.Write([]byte{0})
+
+		if opt.Mode == DirectoryModeFiles {
+			setPath(settings, treePath(name, "."), parseScalar(content))
+			continue
+		}
+
+		ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+		format, ok := supportedFormats[ext]
+		if !ok {
+			continue
+		}
+		parsed, err := parseSettings(string(content), format)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+		settings = deepMerge(settings, parsed)
+	}
+
+	return nestSettings(opt.Prefix, settings), hex.EncodeToString(hash.Sum(nil)), nil
+}
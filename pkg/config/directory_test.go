@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// mountConfigMap 按 Kubernetes 的方式写入一个版本：数据写入带时间戳的目录，
+// 再原子替换 ..data 软链接
+func mountConfigMap(t *testing.T, dir, version string, files map[string]string) {
+	t.Helper()
+	data := filepath.Join(dir, ".."+version)
+	if err := os.Mkdir(data, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		writeFile(t, filepath.Join(data, name), content)
+	}
+
+	tmp := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink(filepath.Base(data), tmp); err != nil {
+		t.Fatal(err)
+	}
+	old, _ := os.Readlink(filepath.Join(dir, "..data"))
+	if err := os.Rename(tmp, filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	if old != "" {
+		if err := os.RemoveAll(filepath.Join(dir, old)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name := range files {
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); err == nil {
+			continue
+		}
+		if err := os.Symlink(filepath.Join("..data", name), link); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDirectoryAdapterSymlinkSwap(t *testing.T) {
+	dir := t.TempDir()
+	mountConfigMap(t, dir, "2026_10_01", map[string]string{
+		"app.yaml": "http:\n  port: 8000\n",
+	})
+
+	v := viper.New()
+	v.Set("config_center", map[string]interface{}{
+		"directory": map[string]interface{}{
+			"path":          dir,
+			"prefix":        "svc",
+			"poll_interval": "0s", // 仅依赖文件事件
+		},
+	})
+
+	adapter := NewDirectoryAdapter()
+	if err := adapter.Init(v); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer adapter.Close()
+
+	if got := v.GetInt("svc.http.port"); got != 8000 {
+		t.Fatalf("svc.http.port = %d, want 8000", got)
+	}
+
+	changed := make(chan struct{}, 10)
+	if err := adapter.Watch(v, func() { changed <- struct{}{} }); err != nil {
+		t.Fatalf("watch failed: %v", err)
+	}
+
+	mountConfigMap(t, dir, "2026_10_02", map[string]string{
+		"app.yaml": "http:\n  port: 9000\n",
+	})
+
+	select {
+	case <-changed:
+	case <-time.After(3 * time.Second):
+		t.Fatal("no reload after ..data swap")
+	}
+	if got := v.GetInt("svc.http.port"); got != 9000 {
+		t.Errorf("svc.http.port = %d, want 9000", got)
+	}
+	if layers := adapter.(LayeredConfigCenter).Layers(); len(layers) != 1 || layers[0].Name != "directory:"+dir {
+		t.Errorf("layers = %+v", layers)
+	}
+}
+
+func TestDirectoryAdapterFilesMode(t *testing.T) {
+	dir := t.TempDir()
+	mountConfigMap(t, dir, "2026_10_01", map[string]string{
+		"db.password": "s3cret\n",
+		"db.port":     "3306",
+	})
+
+	v := viper.New()
+	v.Set("config_center", map[string]interface{}{
+		"directory": map[string]interface{}{"path": dir, "mode": DirectoryModeFiles},
+	})
+
+	adapter := NewDirectoryAdapter()
+	if err := adapter.Init(v); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer adapter.Close()
+
+	if got := v.GetString("db.password"); got != "s3cret" {
+		t.Errorf("db.password = %q, want s3cret", got)
+	}
+	if got := v.GetInt("db.port"); got != 3306 {
+		t.Errorf("db.port = %d, want 3306", got)
+	}
+}
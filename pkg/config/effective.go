@@ -0,0 +1,96 @@
+package config
+
+import (
+	"regexp"
+	"sort"
+)
+
+// RedactedValue 替换敏感配置的值
+const RedactedValue = "******"
+
+// EffectiveValue.Source 的取值前缀
+const (
+	SourceFile    = "file"    // 本地配置文件
+	SourceProfile = "profile" // profile 配置文件，见 config.profile
+	SourceEnv     = "env"     // 环境变量，见 EnvPrefix
+	SourceRuntime = "runtime" // 运行时通过 viper.Set 等方式写入，不属于任何配置来源
+)
+
+// sensitiveKeyPattern 与日志脱敏的默认字段（logger.redaction.fields）保持一致，
+// headers 下常见 Authorization / Cookie 等凭据，整体脱敏
+var sensitiveKeyPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|authorization|id_card|phone|headers|credential|private_key|access_key|api_key)`)
+
+// IsSensitiveKey 判断配置 key（任一层级）是否属于需要脱敏的敏感项
+func IsSensitiveKey(key string) bool {
+	return sensitiveKeyPattern.MatchString(key)
+}
+
+// RedactValue 对敏感 key 的值脱敏，map 与列表中的敏感子项同样处理
+func RedactValue(key string, value interface{}) interface{} {
+	if IsSensitiveKey(key) {
+		if value == nil || value == "" {
+			return value
+		}
+		return RedactedValue
+	}
+	switch t := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			out[k] = RedactValue(k, v)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, v := range t {
+			out[i] = RedactValue("", v)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// EffectiveValue 是合并后生效的单个配置项及其来源
+type EffectiveValue struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+}
+
+// Effective 返回合并后生效的全部配置（已脱敏），按 key 排序。
+// Source 为最后一个提供该 key 的来源：file:<path>、profile:<path>、nacos:<dataId>@<group>、etcd:<key>、
+// snapshot:<name>、env:<变量名> 等。
+func (cm *ConfigManager) Effective() []EffectiveValue {
+	cm.mu.RLock()
+	settings := FlattenSettings(cm.v.AllSettings())
+	layers := make([]SourceLayer, 0, len(cm.localLayers)+len(cm.sources)+len(cm.envLayers))
+	layers = append(layers, cm.localLayers...)
+	for _, src := range cm.sources {
+		layers = append(layers, src.layers...)
+	}
+	layers = append(layers, cm.envLayers...)
+
+	origins := make(map[string]string, len(settings))
+	for _, layer := range layers {
+		for k := range FlattenSettings(layer.Settings) {
+			origins[k] = layer.Name
+		}
+	}
+	cm.mu.RUnlock()
+
+	values := make([]EffectiveValue, 0, len(settings))
+	for k, v := range settings {
+		source, ok := origins[k]
+		if !ok {
+			source = SourceRuntime
+		}
+		values = append(values, EffectiveValue{
+			Key:    k,
+			Value:  RedactValue(k, v),
+			Source: source,
+		})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Key < values[j].Key })
+	return values
+}
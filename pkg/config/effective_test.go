@@ -0,0 +1,68 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRedactValue(t *testing.T) {
+	got := RedactValue("config_center", map[string]interface{}{
+		"nacos": map[string]interface{}{"host": "127.0.0.1", "password": "nacos"},
+		"sources": []interface{}{
+			map[string]interface{}{"type": "consul", "token": "abc"},
+			map[string]interface{}{"type": "http", "headers": map[string]interface{}{"Cookie": "sid=1"}},
+		},
+	}).(map[string]interface{})
+
+	if v := got["nacos"].(map[string]interface{})["password"]; v != RedactedValue {
+		t.Errorf("nacos.password = %v, want redacted", v)
+	}
+	if v := got["nacos"].(map[string]interface{})["host"]; v != "127.0.0.1" {
+		t.Errorf("nacos.host = %v, want 127.0.0.1", v)
+	}
+	if v := got["sources"].([]interface{})[0].(map[string]interface{})["token"]; v != RedactedValue {
+		t.Errorf("sources[0].token = %v, want redacted", v)
+	}
+	if v := got["sources"].([]interface{})[1].(map[string]interface{})["headers"]; v != RedactedValue {
+		t.Errorf("sources[1].headers = %v, want redacted", v)
+	}
+
+	for _, key := range []string{"http.authorization", "user.id_card", "contact.phone", "app.name"} {
+		if got, want := IsSensitiveKey(key), key != "app.name"; got != want {
+			t.Errorf("IsSensitiveKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestEffectiveProfileAndEnv(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, "config.yaml")
+	writeFile(t, local, "config:\n  profile: prod\nhttp:\n  port: 8080\n  mode: debug\napp:\n  name: demo\n")
+	profile := filepath.Join(dir, "config-prod.yaml")
+	writeFile(t, profile, "http:\n  port: 9090\n")
+	t.Setenv("GOBOOT_HTTP_MODE", "release")
+
+	cm, err := NewConfigManager(Options{ConfigFile: ConfigFile(local)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cm.Close()
+
+	if got := cm.GetViper().GetInt("http.port"); got != 9090 {
+		t.Errorf("http.port = %d, want 9090 from profile", got)
+	}
+	if got := cm.GetViper().GetString("http.mode"); got != "release" {
+		t.Errorf("http.mode = %q, want release from env", got)
+	}
+
+	want := map[string]string{
+		"app.name":  SourceFile + ":" + local,
+		"http.port": SourceProfile + ":" + profile,
+		"http.mode": SourceEnv + ":GOBOOT_HTTP_MODE",
+	}
+	for _, ev := range cm.Effective() {
+		if src, ok := want[ev.Key]; ok && ev.Source != src {
+			t.Errorf("%s source = %q, want %q", ev.Key, ev.Source, src)
+		}
+	}
+}
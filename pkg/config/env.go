@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const (
+	// EnvPrefix 是覆盖配置的环境变量前缀，key 中的 . 替换为 _ 并转为大写，
+	// 例如 GOBOOT_HTTP_PORT 覆盖 http.port，GOBOOT_DB_DB_PASSWORD 覆盖 db.db_password
+	EnvPrefix = "GOBOOT_"
+	// EnvProfile 指定 profile，优先于 config.profile
+	EnvProfile = EnvPrefix + "PROFILE"
+)
+
+// profilePath 返回 profile 配置文件路径：与本地配置文件同目录，文件名追加 -<profile>，
+// 例如 config.yaml 的 prod profile 为 config-prod.yaml
+func profilePath(configFile, profile string) string {
+	ext := filepath.Ext(configFile)
+	return strings.TrimSuffix(configFile, ext) + "-" + profile + ext
+}
+
+// loadLocalLayers 返回本地配置文件与 profile 配置文件两层，profile 由 GOBOOT_PROFILE 或 config.profile 指定
+func (cm *ConfigManager) loadLocalLayers(configFile string, settings map[string]interface{}) []SourceLayer {
+	layers := []SourceLayer{{Name: SourceFile + ":" + configFile, Settings: settings}}
+
+	profile := strings.TrimSpace(os.Getenv(EnvProfile))
+	if profile == "" {
+		if section, ok := settings["config"].(map[string]interface{}); ok {
+			profile, _ = section["profile"].(string)
+		}
+	}
+	if profile == "" {
+		return layers
+	}
+
+	path := profilePath(configFile, profile)
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		cm.logger.Warn("failed to load profile config", zap.String("profile", profile), zap.String("file", path), zap.Error(err))
+		return layers
+	}
+	return append(layers, SourceLayer{Name: SourceProfile + ":" + path, Settings: v.AllSettings()})
+}
+
+// envLayers 为每个被环境变量覆盖的 key 生成一层，名称为 env:<变量名>。
+// 候选 key 为当前配置中的 key 与已登记 Option 中的 key
+func envLayers(settings map[string]interface{}) []SourceLayer {
+	candidates := make(map[string]bool)
+	for k := range FlattenSettings(settings) {
+		candidates[k] = true
+	}
+	for _, k := range optionTree().keys("") {
+		candidates[k] = true
+	}
+
+	keys := make([]string, 0, len(candidates))
+	for k := range candidates {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var layers []SourceLayer
+	for _, k := range keys {
+		name := EnvPrefix + strings.ToUpper(strings.ReplaceAll(k, ".", "_"))
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		layer := SourceLayer{Name: SourceEnv + ":" + name, Settings: map[string]interface{}{}}
+		setPath(layer.Settings, strings.Split(k, "."), parseScalar([]byte(raw)))
+		layers = append(layers, layer)
+	}
+	return layers
+}
@@ -0,0 +1,281 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+type etcdOption struct {
+	Endpoints      []string      `mapstructure:"endpoints"`
+	Username       string        `mapstructure:"username"`
+	Password       string        `mapstructure:"password"`
+	DialTimeout    time.Duration `mapstructure:"dial_timeout"`
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	RetryInterval  time.Duration `mapstructure:"retry_interval"` // watch 中断后的重连间隔
+	TLS            tlsOption     `mapstructure:"tls"`
+	Keys           []kvKeyOption `mapstructure:"keys"` // 按声明顺序合并
+}
+
+type etcdEntry struct {
+	kvEntry
+	revision int64
+}
+
+type etcdAdapter struct {
+	mu      sync.Mutex
+	client  *clientv3.Client
+	opt     *etcdOption
+	base    map[string]interface{}
+	entries []*etcdEntry
+	cancel  context.CancelFunc
+	logger  *zap.Logger
+}
+
+func NewEtcdAdapter() ConfigCenter {
+	return &etcdAdapter{logger: zap.NewNop()}
+}
+
+func (e *etcdAdapter) Name() string {
+	return "etcd"
+}
+
+func (e *etcdAdapter) SetLogger(logger *zap.Logger) {
+	e.logger = logger
+}
+
+func defaultEtcdOption() *etcdOption {
+	return &etcdOption{
+		DialTimeout:    5 * time.Second,
+		RequestTimeout: 5 * time.Second,
+		RetryInterval:  3 * time.Second,
+	}
+}
+
+func loadEtcdOption(v *viper.Viper) (*etcdOption, error) {
+	sub := v.Sub("config_center.etcd")
+	if sub == nil {
+		return nil, fmt.Errorf("missing etcd config block in viper")
+	}
+	opt := defaultEtcdOption()
+	if err := sub.Unmarshal(opt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal etcd options: %w", err)
+	}
+	if len(opt.Endpoints) == 0 {
+		return nil, fmt.Errorf("no etcd endpoints configured")
+	}
+	if len(opt.Keys) == 0 {
+		return nil, fmt.Errorf("no etcd keys configured")
+	}
+	return opt, nil
+}
+
+func (e *etcdAdapter) Init(v *viper.Viper) error {
+	opt, err := loadEtcdOption(v)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]*etcdEntry, 0, len(opt.Keys))
+	for _, item := range opt.Keys {
+		entry, err := newKVEntry(item)
+		if err != nil {
+			return fmt.Errorf("etcd: %w", err)
+		}
+		entries = append(entries, &etcdEntry{kvEntry: *entry})
+	}
+
+	clientCfg := clientv3.Config{
+		Endpoints:   opt.Endpoints,
+		Username:    opt.Username,
+		Password:    opt.Password,
+		DialTimeout: opt.DialTimeout,
+	}
+	if opt.TLS.Enabled {
+		tlsCfg, err := opt.TLS.build()
+		if err != nil {
+			return err
+		}
+		clientCfg.TLS = tlsCfg
+	}
+
+	client, err := clientv3.New(clientCfg)
+	if err != nil {
+		return fmt.Errorf("failed to create etcd client: %w", err)
+	}
+
+	for _, entry := range entries {
+		e.logger.Info("initializing", zap.Stringer("key", entry), zap.Bool("tree", entry.tree))
+		if err = e.load(client, opt, entry); err != nil {
+			_ = client.Close()
+			return err
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.client = client
+	e.opt = opt
+	e.base = cloneSettings(v.AllSettings())
+	e.entries = entries
+	e.applyLocked(v)
+
+	return nil
+}
+
+// load 全量拉取 entry 并记录 revision
+func (e *etcdAdapter) load(client *clientv3.Client, opt *etcdOption, entry *etcdEntry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), opt.RequestTimeout)
+	defer cancel()
+
+	var ops []clientv3.OpOption
+	if entry.tree {
+		ops = append(ops, clientv3.WithPrefix())
+	}
+	resp, err := client.Get(ctx, entry.key, ops...)
+	if err != nil {
+		return fmt.Errorf("failed to get config %s from etcd: %w", entry, err)
+	}
+
+	values := make(map[string][]byte, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		values[string(kv.Key)] = kv.Value
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	entry.values = values
+	entry.revision = resp.Header.Revision
+	if err = entry.rebuild(); err != nil {
+		return fmt.Errorf("failed to parse etcd config %s: %w", entry, err)
+	}
+	return nil
+}
+
+func (e *etcdAdapter) Watch(v *viper.Viper, onChange func()) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.client == nil {
+		return fmt.Errorf("etcd client not initialized")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+
+	for _, entry := range e.entries {
+		if !entry.refresh {
+			continue
+		}
+		go e.watchEntry(ctx, e.client, v, entry, onChange)
+	}
+	return nil
+}
+
+// watchEntry 从上次 revision 之后开始监听，中断或被压缩时重新全量拉取后继续监听
+func (e *etcdAdapter) watchEntry(ctx context.Context, client *clientv3.Client, v *viper.Viper, entry *etcdEntry, onChange func()) {
+	opt := e.opt
+	for {
+		e.mu.Lock()
+		rev := entry.revision
+		e.mu.Unlock()
+
+		ops := []clientv3.OpOption{clientv3.WithRev(rev + 1)}
+		if entry.tree {
+			ops = append(ops, clientv3.WithPrefix())
+		}
+
+		for resp := range client.Watch(clientv3.WithRequireLeader(ctx), entry.key, ops...) {
+			if err := resp.Err(); err != nil {
+				e.logger.Warn("watch interrupted", zap.Stringer("key", entry), zap.Error(err))
+				break
+			}
+			if len(resp.Events) == 0 {
+				continue
+			}
+
+			e.mu.Lock()
+			for _, ev := range resp.Events {
+				k := string(ev.Kv.Key)
+				if ev.Type == clientv3.EventTypeDelete {
+					delete(entry.values, k)
+				} else {
+					entry.values[k] = ev.Kv.Value
+				}
+			}
+			entry.revision = resp.Header.Revision
+			err := entry.rebuild()
+			if err == nil {
+				e.applyLocked(v)
+			}
+			e.mu.Unlock()
+
+			if err != nil {
+				e.logger.Error("failed to merge config", zap.Stringer("key", entry), zap.Error(err))
+				continue
+			}
+
+			e.logger.Info("config changed, reloading", zap.Stringer("key", entry))
+			onChange()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(opt.RetryInterval):
+		}
+
+		// 重新同步，补齐中断期间的变更
+		if err := e.load(client, opt, entry); err != nil {
+			e.logger.Warn("resync failed", zap.Stringer("key", entry), zap.Error(err))
+			continue
+		}
+		e.mu.Lock()
+		e.applyLocked(v)
+		e.mu.Unlock()
+		onChange()
+	}
+}
+
+func (e *etcdAdapter) applyLocked(v *viper.Viper) {
+	merged := cloneSettings(e.base)
+	for _, entry := range e.entries {
+		merged = deepMerge(merged, entry.settings)
+	}
+	replaceSettings(v, merged)
+}
+
+func (e *etcdAdapter) Layers() []SourceLayer {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	layers := make([]SourceLayer, 0, len(e.entries))
+	for _, entry := range e.entries {
+		layers = append(layers, SourceLayer{
+			Name:     "etcd:" + entry.String(),
+			Settings: cloneSettings(entry.settings),
+		})
+	}
+	return layers
+}
+
+func (e *etcdAdapter) Close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.cancel != nil {
+		e.cancel()
+		e.cancel = nil
+	}
+	if e.client != nil {
+		_ = e.client.Close()
+		e.client = nil
+	}
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+func freeURL(t *testing.T) url.URL {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to allocate port: %v", err)
+	}
+	defer l.Close()
+	return url.URL{Scheme: "http", Host: l.Addr().String()}
+}
+
+func startEtcd(t *testing.T) string {
+	t.Helper()
+
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "fatal"
+	clientURL, peerURL := freeURL(t), freeURL(t)
+	cfg.ListenClientUrls = []url.URL{clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{clientURL}
+	cfg.ListenPeerUrls = []url.URL{peerURL}
+	cfg.AdvertisePeerUrls = []url.URL{peerURL}
+	cfg.InitialCluster = fmt.Sprintf("%s=%s", cfg.Name, peerURL.String())
+
+	server, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatalf("failed to start embedded etcd: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	select {
+	case <-server.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.Fatal("embedded etcd not ready")
+	}
+	return clientURL.Host
+}
+
+func TestEtcdAdapter(t *testing.T) {
+	endpoint := startEtcd(t)
+
+	client, err := clientv3.New(clientv3.Config{Endpoints: []string{endpoint}, DialTimeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("failed to create etcd client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	mustPut := func(k, v string) {
+		if _, err := client.Put(ctx, k, v); err != nil {
+			t.Fatalf("put %s failed: %v", k, err)
+		}
+	}
+	mustPut("/goboot/config.yaml", "http:\n  port: 8081\napp:\n  name: from-etcd\n")
+	mustPut("/goboot/flags/checkout/enabled", "true")
+	mustPut("/goboot/flags/checkout/ratio", "0.5")
+
+	v := viper.New()
+	v.Set("app", map[string]interface{}{"name": "local", "env": "test"})
+	v.Set("config_center", map[string]interface{}{
+		"etcd": map[string]interface{}{
+			"endpoints":      []string{endpoint},
+			"retry_interval": "100ms",
+			"keys": []map[string]interface{}{
+				{"key": "/goboot/config.yaml"},
+				{"key": "/goboot/flags/", "tree": true, "prefix": "feature"},
+			},
+		},
+	})
+
+	adapter := NewEtcdAdapter()
+	if err = adapter.Init(v); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer adapter.Close()
+
+	if got := v.GetInt("http.port"); got != 8081 {
+		t.Errorf("http.port = %d, want 8081", got)
+	}
+	if got := v.GetString("app.name"); got != "from-etcd" {
+		t.Errorf("app.name = %q, want from-etcd", got)
+	}
+	if got := v.GetString("app.env"); got != "test" {
+		t.Errorf("app.env = %q, want local value kept", got)
+	}
+	if !v.GetBool("feature.checkout.enabled") || v.GetFloat64("feature.checkout.ratio") != 0.5 {
+		t.Errorf("feature tree not mapped: %v", v.Get("feature"))
+	}
+
+	changed := make(chan struct{}, 10)
+	if err = adapter.Watch(v, func() { changed <- struct{}{} }); err != nil {
+		t.Fatalf("watch failed: %v", err)
+	}
+
+	mustPut("/goboot/flags/checkout/ratio", "0.8")
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change notification")
+	}
+	if got := v.GetFloat64("feature.checkout.ratio"); got != 0.8 {
+		t.Errorf("feature.checkout.ratio = %v, want 0.8", got)
+	}
+
+	if _, err = client.Delete(ctx, "/goboot/flags/checkout/enabled"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change notification for delete")
+	}
+	if v.IsSet("feature.checkout.enabled") {
+		t.Errorf("feature.checkout.enabled should be removed")
+	}
+}
@@ -0,0 +1,303 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type httpSourceOption struct {
+	URL      string            `mapstructure:"url"`
+	Interval time.Duration     `mapstructure:"interval"` // 轮询间隔
+	Timeout  time.Duration     `mapstructure:"timeout"`
+	Token    string            `mapstructure:"token"` // Bearer token
+	Headers  map[string]string `mapstructure:"headers"`
+	Format   string            `mapstructure:"format"` // 为空时按 Content-Type、URL 后缀推断
+	Prefix   string            `mapstructure:"prefix"`
+	TLS      tlsOption         `mapstructure:"tls"`
+}
+
+var contentTypeFormats = map[string]string{
+	"application/json":              "json",
+	"application/yaml":              "yaml",
+	"application/x-yaml":            "yaml",
+	"text/yaml":                     "yaml",
+	"text/x-yaml":                   "yaml",
+	"application/toml":              "toml",
+	"text/x-toml":                   "toml",
+	"text/x-java-properties":        "properties",
+	"text/x-properties":             "properties",
+	"application/x-properties":      "properties",
+	"application/x-java-properties": "properties",
+}
+
+// httpAdapter 轮询 HTTP(S) 地址获取配置，使用 ETag / If-None-Match 避免重复下载
+type httpAdapter struct {
+	mu       sync.Mutex
+	client   *http.Client
+	opt      *httpSourceOption
+	base     map[string]interface{}
+	settings map[string]interface{}
+	etag     string
+	digest   string
+	cancel   context.CancelFunc
+	logger   *zap.Logger
+}
+
+func NewHTTPAdapter() ConfigCenter {
+	return &httpAdapter{logger: zap.NewNop()}
+}
+
+func (h *httpAdapter) Name() string {
+	return "http"
+}
+
+func (h *httpAdapter) SetLogger(logger *zap.Logger) {
+	h.logger = logger
+}
+
+func defaultHTTPSourceOption() *httpSourceOption {
+	return &httpSourceOption{
+		Interval: 30 * time.Second,
+		Timeout:  10 * time.Second,
+	}
+}
+
+func loadHTTPSourceOption(v *viper.Viper) (*httpSourceOption, error) {
+	sub := v.Sub("config_center.http")
+	if sub == nil {
+		return nil, fmt.Errorf("missing http config block in viper")
+	}
+	opt := defaultHTTPSourceOption()
+	if err := sub.Unmarshal(opt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal http source options: %w", err)
+	}
+	if opt.URL == "" {
+		return nil, fmt.Errorf("no config url configured")
+	}
+	if opt.Interval <= 0 {
+		return nil, fmt.Errorf("invalid http source interval: %s", opt.Interval)
+	}
+	return opt, nil
+}
+
+func (h *httpAdapter) Init(v *viper.Viper) error {
+	opt, err := loadHTTPSourceOption(v)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: opt.Timeout}
+	if opt.TLS.Enabled {
+		tlsCfg, err := opt.TLS.build()
+		if err != nil {
+			return err
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsCfg
+		client.Transport = transport
+	}
+
+	h.logger.Info("initializing", zap.String("url", opt.URL))
+
+	result, err := fetchHTTP(context.Background(), client, opt, "", "")
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.client = client
+	h.opt = opt
+	h.etag = result.etag
+	h.digest = result.digest
+	h.settings = result.settings
+	h.base = cloneSettings(v.AllSettings())
+	h.applyLocked(v)
+
+	return nil
+}
+
+// httpFetchResult 是一次拉取的结果，changed 为 false 时 settings 为空
+type httpFetchResult struct {
+	changed  bool
+	etag     string
+	digest   string
+	settings map[string]interface{}
+}
+
+// fetchHTTP 拉取配置，etag 与 digest 为上次拉取的结果，用于判断内容是否变化。
+// 不访问 httpAdapter 的状态，调用方无需持有锁
+func fetchHTTP(ctx context.Context, client *http.Client, opt *httpSourceOption, etag, digest string) (httpFetchResult, error) {
+	unchanged := httpFetchResult{etag: etag, digest: digest}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, opt.URL, nil)
+	if err != nil {
+		return unchanged, fmt.Errorf("failed to build config request: %w", err)
+	}
+	for k, val := range opt.Headers {
+		req.Header.Set(k, val)
+	}
+	if opt.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+opt.Token)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return unchanged, fmt.Errorf("failed to fetch config from %s: %w", opt.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return unchanged, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return unchanged, fmt.Errorf("failed to fetch config from %s: unexpected status %s", opt.URL, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return unchanged, fmt.Errorf("failed to read config from %s: %w", opt.URL, err)
+	}
+
+	// 服务端不支持 ETag 时按内容摘要判断是否变化
+	sum := sha256.Sum256(body)
+	unchanged.etag = resp.Header.Get("ETag")
+	if hex.EncodeToString(sum[:]) == digest {
+		return unchanged, nil
+	}
+
+	format, err := opt.detectFormat(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return unchanged, err
+	}
+	settings, err := parseSettings(string(body), format)
+	if err != nil {
+		return unchanged, fmt.Errorf("failed to parse config from %s: %w", opt.URL, err)
+	}
+
+	return httpFetchResult{
+		changed:  true,
+		etag:     unchanged.etag,
+		digest:   hex.EncodeToString(sum[:]),
+		settings: nestSettings(opt.Prefix, settings),
+	}, nil
+}
+
+func (opt *httpSourceOption) detectFormat(contentType string) (string, error) {
+	if opt.Format != "" {
+		return normalizeFormat(opt.Format, "")
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if format, ok := contentTypeFormats[mediaType]; ok {
+			return format, nil
+		}
+	}
+	path := opt.URL
+	if u, err := url.Parse(opt.URL); err == nil {
+		path = u.Path
+	}
+	return normalizeFormat("", strings.ToLower(path))
+}
+
+func (h *httpAdapter) Watch(v *viper.Viper, onChange func()) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.client == nil {
+		return fmt.Errorf("http source not initialized")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	go h.poll(ctx, v, onChange)
+	return nil
+}
+
+func (h *httpAdapter) poll(ctx context.Context, v *viper.Viper, onChange func()) {
+	ticker := time.NewTicker(h.opt.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// 拉取期间不持有锁，避免慢请求阻塞 Layers 与 Close
+		h.mu.Lock()
+		client, opt, etag, digest := h.client, h.opt, h.etag, h.digest
+		h.mu.Unlock()
+
+		result, err := fetchHTTP(ctx, client, opt, etag, digest)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			h.logger.Warn("poll failed", zap.String("url", opt.URL), zap.Error(err))
+			continue
+		}
+
+		h.mu.Lock()
+		// Close 在持有锁时取消 ctx，这里再次检查，避免关闭后仍写入 v
+		if ctx.Err() != nil {
+			h.mu.Unlock()
+			return
+		}
+		h.etag = result.etag
+		if result.changed {
+			h.digest = result.digest
+			h.settings = result.settings
+			h.applyLocked(v)
+		}
+		h.mu.Unlock()
+
+		if result.changed {
+			h.logger.Info("config changed, reloading", zap.String("url", opt.URL))
+			onChange()
+		}
+	}
+}
+
+func (h *httpAdapter) applyLocked(v *viper.Viper) {
+	replaceSettings(v, deepMerge(cloneSettings(h.base), h.settings))
+}
+
+func (h *httpAdapter) Layers() []SourceLayer {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.opt == nil {
+		return nil
+	}
+	return []SourceLayer{{
+		Name:     "http:" + h.opt.URL,
+		Settings: cloneSettings(h.settings),
+	}}
+}
+
+func (h *httpAdapter) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cancel != nil {
+		h.cancel()
+		h.cancel = nil
+	}
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// fakeConfigServer 按 ETag 返回配置，请求头与 If-None-Match 匹配时返回 304
+type fakeConfigServer struct {
+	mu          sync.Mutex
+	body        string
+	contentType string
+	etag        string
+	notModified int
+	block       chan struct{} // 非空时请求阻塞到通道关闭
+}
+
+func (f *fakeConfigServer) set(body, etag string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.body = body
+	f.etag = etag
+}
+
+func (f *fakeConfigServer) notModifiedCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.notModified
+}
+
+func (f *fakeConfigServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	block := f.block
+	f.mu.Unlock()
+	if block != nil {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.etag != "" && r.Header.Get("If-None-Match") == f.etag {
+		f.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if f.etag != "" {
+		w.Header().Set("ETag", f.etag)
+	}
+	if f.contentType != "" {
+		w.Header().Set("Content-Type", f.contentType)
+	}
+	_, _ = w.Write([]byte(f.body))
+}
+
+func httpSourceViper(conf map[string]interface{}) *viper.Viper {
+	v := viper.New()
+	v.Set("config_center", map[string]interface{}{"http": conf})
+	return v
+}
+
+func TestHTTPAdapterETag(t *testing.T) {
+	fake := &fakeConfigServer{body: "http:\n  port: 8000\n", contentType: "application/yaml", etag: `"v1"`}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	v := httpSourceViper(map[string]interface{}{"url": server.URL + "/config", "interval": "20ms"})
+	adapter := NewHTTPAdapter()
+	if err := adapter.Init(v); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer adapter.Close()
+	if got := v.GetInt("http.port"); got != 8000 {
+		t.Fatalf("http.port = %d, want 8000", got)
+	}
+
+	changed := make(chan struct{}, 10)
+	if err := adapter.Watch(v, func() { changed <- struct{}{} }); err != nil {
+		t.Fatalf("watch failed: %v", err)
+	}
+
+	// 内容未变化时服务端返回 304，不触发变更
+	deadline := time.Now().Add(3 * time.Second)
+	for fake.notModifiedCount() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("poll did not send If-None-Match")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case <-changed:
+		t.Fatal("unexpected change on 304")
+	default:
+	}
+
+	fake.set("http:\n  port: 9000\n", `"v2"`)
+	select {
+	case <-changed:
+	case <-time.After(3 * time.Second):
+		t.Fatal("no change after etag update")
+	}
+	if got := v.GetInt("http.port"); got != 9000 {
+		t.Errorf("http.port = %d, want 9000", got)
+	}
+}
+
+func TestHTTPAdapterAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" || r.Header.Get("X-Env") != "prod" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"app": {"name": "demo"}}`))
+	}))
+	defer server.Close()
+
+	conf := map[string]interface{}{
+		"url":     server.URL + "/config.json",
+		"headers": map[string]interface{}{"X-Env": "prod"},
+	}
+	if err := NewHTTPAdapter().Init(httpSourceViper(conf)); err == nil {
+		t.Error("expected error without token")
+	}
+
+	conf["token"] = "s3cret"
+	v := httpSourceViper(conf)
+	adapter := NewHTTPAdapter()
+	if err := adapter.Init(v); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer adapter.Close()
+	if got := v.GetString("app.name"); got != "demo" {
+		t.Errorf("app.name = %q, want demo", got)
+	}
+}
+
+func TestHTTPAdapterFormatDetection(t *testing.T) {
+	cases := []struct {
+		name        string
+		path        string
+		contentType string
+		format      string
+		body        string
+	}{
+		{"content type", "/config", "application/json; charset=utf-8", "", `{"feature": {"enabled": true}}`},
+		{"content type over suffix", "/config.yaml", "text/x-java-properties", "", "feature.enabled=true"},
+		{"url suffix", "/config.toml", "text/plain", "", "[feature]\nenabled = true\n"},
+		{"explicit format", "/config", "application/json", "yaml", "feature:\n  enabled: true\n"},
+	}
+	for _, tc := range cases {
+		server := httptest.NewServer(&fakeConfigServer{body: tc.body, contentType: tc.contentType})
+		conf := map[string]interface{}{"url": server.URL + tc.path, "prefix": "svc"}
+		if tc.format != "" {
+			conf["format"] = tc.format
+		}
+		v := httpSourceViper(conf)
+		adapter := NewHTTPAdapter()
+		if err := adapter.Init(v); err != nil {
+			t.Errorf("%s: init failed: %v", tc.name, err)
+		} else if !v.GetBool("svc.feature.enabled") {
+			t.Errorf("%s: svc.feature.enabled = false, settings = %v", tc.name, v.AllSettings())
+		}
+		adapter.Close()
+		server.Close()
+	}
+}
+
+func TestHTTPAdapterPollDoesNotHoldLock(t *testing.T) {
+	fake := &fakeConfigServer{body: `{"http": {"port": 8000}}`, contentType: "application/json"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	v := httpSourceViper(map[string]interface{}{"url": server.URL, "interval": "10ms"})
+	adapter := NewHTTPAdapter()
+	if err := adapter.Init(v); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	release := make(chan struct{})
+	fake.mu.Lock()
+	fake.block = release
+	fake.mu.Unlock()
+	defer close(release)
+
+	if err := adapter.Watch(v, func() {}); err != nil {
+		t.Fatalf("watch failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond) // 等待 poll 阻塞在请求上
+
+	done := make(chan struct{})
+	go func() {
+		adapter.(LayeredConfigCenter).Layers()
+		adapter.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Layers/Close blocked by an in-flight poll")
+	}
+}
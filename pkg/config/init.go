@@ -1,5 +1,5 @@
 package config
 
-func InitConfigManager(opt Options) *ConfigManager {
+func InitConfigManager(opt Options) (*ConfigManager, error) {
 	return NewConfigManager(opt)
 }
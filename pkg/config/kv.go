@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kvKeyOption 描述 KV 类配置中心（etcd、Consul）中的一个配置 key
+type kvKeyOption struct {
+	Key     string `mapstructure:"key"`
+	Tree    bool   `mapstructure:"tree"`                   // 为 true 时 key 作为前缀，其下每个路径映射为嵌套配置
+	Format  string `mapstructure:"format"`                 // 非 tree 模式下 value 的格式，yaml | json | properties | toml
+	Prefix  string `mapstructure:"prefix"`                 // 挂载到指定 key 之下
+	Refresh *bool  `mapstructure:"refresh" default:"true"` // 是否监听变更，默认 true
+}
+
+type kvEntry struct {
+	key      string
+	tree     bool
+	format   string
+	prefix   string
+	refresh  bool
+	values   map[string][]byte
+	settings map[string]interface{}
+}
+
+func newKVEntry(item kvKeyOption) (*kvEntry, error) {
+	if item.Key == "" {
+		return nil, fmt.Errorf("key must not be empty")
+	}
+	format, err := normalizeFormat(item.Format, item.Key)
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", item.Key, err)
+	}
+	key := item.Key
+	// tree 模式按前缀查询，补齐末尾的 /，避免 config/app 匹配到 config/app-old 下的 key
+	if item.Tree && !strings.HasSuffix(key, "/") {
+		key += "/"
+	}
+	return &kvEntry{
+		key:     key,
+		tree:    item.Tree,
+		format:  format,
+		prefix:  item.Prefix,
+		refresh: item.Refresh == nil || *item.Refresh,
+		values:  make(map[string][]byte),
+	}, nil
+}
+
+func (e *kvEntry) String() string {
+	return e.key
+}
+
+// rebuild 根据 values 重新计算 settings
+func (e *kvEntry) rebuild() error {
+	if !e.tree {
+		content, ok := e.values[e.key]
+		if !ok {
+			e.settings = map[string]interface{}{}
+			return nil
+		}
+		settings, err := parseSettings(string(content), e.format)
+		if err != nil {
+			return err
+		}
+		e.settings = nestSettings(e.prefix, settings)
+		return nil
+	}
+
+	keys := make([]string, 0, len(e.values))
+	for k := range e.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	settings := make(map[string]interface{})
+	for _, k := range keys {
+		rel, ok := strings.CutPrefix(k, e.key)
+		if !ok {
+			continue
+		}
+		path := treePath(rel, "/")
+		if len(path) == 0 {
+			continue
+		}
+		setPath(settings, path, parseScalar(e.values[k]))
+	}
+	e.settings = nestSettings(e.prefix, settings)
+	return nil
+}
+
+// treePath 将 "/a/b/c" 转换为 ["a", "b", "c"]
+func treePath(rel, sep string) []string {
+	parts := strings.Split(strings.ToLower(rel), sep)
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// setPath 在嵌套 map 中按路径写入值
+func setPath(m map[string]interface{}, path []string, val interface{}) {
+	for i, p := range path {
+		if i == len(path)-1 {
+			m[p] = val
+			return
+		}
+		next, ok := m[p].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[p] = next
+		}
+		m = next
+	}
+}
+
+// parseScalar 将单个值按 YAML 解析（数字、布尔、列表等），失败时保留原始字符串
+func parseScalar(raw []byte) interface{} {
+	var val interface{}
+	if err := yaml.Unmarshal(raw, &val); err != nil || val == nil {
+		return string(raw)
+	}
+	if m, ok := val.(map[string]interface{}); ok {
+		return m
+	}
+	return val
+}
@@ -0,0 +1,25 @@
+package config
+
+import "testing"
+
+func TestKVEntryTreePrefix(t *testing.T) {
+	entry, err := newKVEntry(kvKeyOption{Key: "config/app", Tree: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.key != "config/app/" {
+		t.Errorf("key = %q, want config/app/", entry.key)
+	}
+
+	entry.values = map[string][]byte{
+		"config/app/db/host":     []byte("10.0.0.1"),
+		"config/app-old/db/host": []byte("10.0.0.2"),
+	}
+	if err = entry.rebuild(); err != nil {
+		t.Fatal(err)
+	}
+	db, _ := entry.settings["db"].(map[string]interface{})
+	if len(entry.settings) != 1 || db["host"] != "10.0.0.1" {
+		t.Errorf("settings = %v, want only config/app/db/host", entry.settings)
+	}
+}
@@ -2,133 +2,515 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/nacos-group/nacos-sdk-go/clients"
 	"github.com/nacos-group/nacos-sdk-go/clients/config_client"
 	"github.com/nacos-group/nacos-sdk-go/common/constant"
 	"github.com/nacos-group/nacos-sdk-go/vo"
 	"github.com/spf13/viper"
+	"go.uber.org/zap"
 )
 
+const (
+	defaultNacosGroup       = "DEFAULT_GROUP"
+	defaultNacosContextPath = "/nacos"
+	defaultNacosPort        = 8848
+)
+
+// NacosClientOption 是 Nacos 连接参数，配置中心与服务发现共用
+type NacosClientOption struct {
+	// 单节点地址，server_addrs 为空时使用
+	Host string `mapstructure:"host"`
+	Port uint64 `mapstructure:"port"`
+	// 集群地址，支持 "host:port" 或 "https://host:port/nacos"
+	ServerAddrs []string `mapstructure:"server_addrs"`
+	// 地址服务器，配置后由 Nacos SDK 动态获取集群地址
+	Endpoint    string        `mapstructure:"endpoint"`
+	ContextPath string        `mapstructure:"context_path"`
+	Timeout     time.Duration `mapstructure:"timeout"`
+	Namespace   string        `mapstructure:"namespace"`
+	LogDir      string        `mapstructure:"log_dir"`
+	CacheDir    string        `mapstructure:"cache_dir"`
+	LogLevel    string        `mapstructure:"log_level"`
+	// 为 false 时启动时先加载 cache_dir 中的本地缓存
+	NotLoadCacheAtStart bool `mapstructure:"not_load_cache_at_start"`
+
+	// 鉴权：用户名密码或 AccessKey/SecretKey
+	Username  string `mapstructure:"username"`
+	Password  string `mapstructure:"password"`
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+
+	TLS nacosTLSOption `mapstructure:"tls"`
+}
+
+// nacosTLSOption 只提供 enabled：Nacos SDK v1 的 http 请求固定使用进程共享的 http.DefaultTransport，
+// 无法单独指定 CA、客户端证书等，服务端证书需由系统信任
+type nacosTLSOption struct {
+	Enabled bool `mapstructure:"enabled"` // 使用 https
+}
+
+// DefaultNacosClientOption 返回 Nacos 连接参数的默认值
+func DefaultNacosClientOption() NacosClientOption {
+	return NacosClientOption{
+		ContextPath:         defaultNacosContextPath,
+		Timeout:             5 * time.Second,
+		NotLoadCacheAtStart: true,
+	}
+}
+
+type nacosOption struct {
+	NacosClientOption `mapstructure:",squash"`
+
+	// 主配置，优先级最高
+	DataID string `mapstructure:"data_id"`
+	Group  string `mapstructure:"group"`
+	Format string `mapstructure:"format"`
+
+	// 共享配置与扩展配置，按声明顺序合并（同 Spring Cloud Alibaba shared-configs / extension-configs）
+	SharedConfigs    []nacosDataOption `mapstructure:"shared_configs"`
+	ExtensionConfigs []nacosDataOption `mapstructure:"extension_configs"`
+}
+
+type nacosDataOption struct {
+	DataID  string `mapstructure:"data_id"`
+	Group   string `mapstructure:"group"`
+	Format  string `mapstructure:"format"`                 // yaml | json | properties | toml，为空时按 data_id 后缀推断
+	Prefix  string `mapstructure:"prefix"`                 // 挂载到指定 key 之下，例如 "feature"
+	Refresh *bool  `mapstructure:"refresh" default:"true"` // 是否监听变更，默认 true
+}
+
+// dataOptions 返回按合并顺序排列的数据项：shared → extension → 主配置
+func (o *nacosOption) dataOptions() []nacosDataOption {
+	items := make([]nacosDataOption, 0, len(o.SharedConfigs)+len(o.ExtensionConfigs)+1)
+	items = append(items, o.SharedConfigs...)
+	items = append(items, o.ExtensionConfigs...)
+	if o.DataID != "" {
+		items = append(items, nacosDataOption{
+			DataID: o.DataID,
+			Group:  o.Group,
+			Format: o.Format,
+		})
+	}
+	return items
+}
+
+type nacosEntry struct {
+	dataID   string
+	group    string
+	format   string
+	prefix   string
+	refresh  bool
+	settings map[string]interface{}
+}
+
+func (e *nacosEntry) String() string {
+	return e.dataID + "@" + e.group
+}
+
 type nacosAdapter struct {
-	client config_client.IConfigClient
+	mu      sync.Mutex
+	client  config_client.IConfigClient
+	param   vo.NacosClientParam // 创建 client 所用的参数，参数不变时重复 Init 复用同一 client
+	group   string              // 主配置分组，发布时 key 未指定分组则使用该分组
+	base    map[string]interface{}
+	entries []*nacosEntry
+	logger  *zap.Logger
 }
 
 func NewNacosAdapter() ConfigCenter {
-	return &nacosAdapter{}
+	return &nacosAdapter{logger: zap.NewNop()}
 }
 
 func (n *nacosAdapter) Name() string {
 	return "nacos"
 }
 
-func (n *nacosAdapter) Init(v *viper.Viper) error {
+func (n *nacosAdapter) SetLogger(logger *zap.Logger) {
+	n.logger = logger
+}
+
+func defaultNacosOption() *nacosOption {
+	return &nacosOption{
+		NacosClientOption: DefaultNacosClientOption(),
+		Group:             defaultNacosGroup,
+	}
+}
 
+func loadNacosOption(v *viper.Viper) (*nacosOption, error) {
 	sub := v.Sub("config_center.nacos")
 	if sub == nil {
-		return fmt.Errorf("missing nacos config block in viper")
+		return nil, fmt.Errorf("missing nacos config block in viper")
+	}
+	opt := defaultNacosOption()
+	if err := sub.Unmarshal(opt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal nacos options: %w", err)
+	}
+	return opt, nil
+}
+
+// ClientParam 构建 Nacos SDK 客户端参数
+func (opt *NacosClientOption) ClientParam() (vo.NacosClientParam, error) {
+	scheme := "http"
+	if opt.TLS.Enabled {
+		scheme = "https"
 	}
 
-	serverConfig := constant.ServerConfig{
-		IpAddr:      sub.GetString("host"),
-		Port:        uint64(sub.GetUint("port")),
-		ContextPath: "/nacos",
+	addrs := opt.ServerAddrs
+	if len(addrs) == 0 && opt.Host != "" {
+		addrs = []string{net.JoinHostPort(opt.Host, strconv.FormatUint(opt.Port, 10))}
+	}
+	if len(addrs) == 0 && opt.Endpoint == "" {
+		return vo.NacosClientParam{}, fmt.Errorf("no nacos server address configured")
 	}
 
-	fmt.Println("[Nacos] parsed server config:", serverConfig)
+	serverConfigs := make([]constant.ServerConfig, 0, len(addrs))
+	for _, addr := range addrs {
+		serverConfig, err := parseNacosServerAddr(addr, scheme, opt.ContextPath)
+		if err != nil {
+			return vo.NacosClientParam{}, err
+		}
+		serverConfigs = append(serverConfigs, serverConfig)
+	}
 
 	clientConfig := constant.ClientConfig{
-		NamespaceId:         sub.GetString("namespace"),
-		TimeoutMs:           5000,
-		NotLoadCacheAtStart: true,
-		LogDir:              sub.GetString("log_dir"),
-		CacheDir:            sub.GetString("cache_dir"),
+		NamespaceId:         opt.Namespace,
+		Endpoint:            opt.Endpoint,
+		ContextPath:         opt.ContextPath,
+		TimeoutMs:           uint64(opt.Timeout.Milliseconds()),
+		NotLoadCacheAtStart: opt.NotLoadCacheAtStart,
+		LogDir:              opt.LogDir,
+		CacheDir:            opt.CacheDir,
+		LogLevel:            opt.LogLevel,
+		Username:            opt.Username,
+		Password:            opt.Password,
+		AccessKey:           opt.AccessKey,
+		SecretKey:           opt.SecretKey,
+	}
+
+	return vo.NacosClientParam{
+		ClientConfig:  &clientConfig,
+		ServerConfigs: serverConfigs,
+	}, nil
+}
+
+// parseNacosServerAddr 解析 "host:port" 或 "scheme://host:port/context" 形式的地址
+func parseNacosServerAddr(addr, scheme, contextPath string) (constant.ServerConfig, error) {
+	addr = strings.TrimSpace(addr)
+	if !strings.Contains(addr, "://") {
+		addr = scheme + "://" + addr
 	}
 
-	client, err := clients.NewConfigClient(
-		vo.NacosClientParam{
-			ClientConfig:  &clientConfig,
-			ServerConfigs: []constant.ServerConfig{serverConfig},
-		},
-	)
+	u, err := url.Parse(addr)
 	if err != nil {
-		return fmt.Errorf("failed to create nacos client: %w", err)
+		return constant.ServerConfig{}, fmt.Errorf("invalid nacos server address %q: %w", addr, err)
 	}
 
-	n.client = client
+	port := uint64(defaultNacosPort)
+	if p := u.Port(); p != "" {
+		port, err = strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return constant.ServerConfig{}, fmt.Errorf("invalid nacos server port %q: %w", addr, err)
+		}
+	}
 
-	// 添加到 Init() 的最后
-	dataID := sub.GetString("data_id")
-	group := sub.GetString("group")
+	if path := strings.TrimRight(u.Path, "/"); path != "" {
+		contextPath = path
+	}
 
-	fmt.Println("[Nacos] Initializing with dataID:", dataID, "group:", group, "namespace:", v.GetString("namespace"))
+	return constant.ServerConfig{
+		Scheme:      u.Scheme,
+		IpAddr:      u.Hostname(),
+		Port:        port,
+		ContextPath: contextPath,
+	}, nil
+}
 
-	content, err := n.client.GetConfig(vo.ConfigParam{
-		DataId: dataID,
-		Group:  group,
-	})
+func newNacosEntries(opt *nacosOption) ([]*nacosEntry, error) {
+	items := opt.dataOptions()
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no nacos data_id configured")
+	}
+
+	entries := make([]*nacosEntry, 0, len(items))
+	for _, item := range items {
+		if item.DataID == "" {
+			return nil, fmt.Errorf("nacos data_id must not be empty")
+		}
+		format, err := normalizeFormat(item.Format, item.DataID)
+		if err != nil {
+			return nil, fmt.Errorf("nacos data_id %s: %w", item.DataID, err)
+		}
+		group := item.Group
+		if group == "" {
+			group = defaultNacosGroup
+		}
+		entries = append(entries, &nacosEntry{
+			dataID:  item.DataID,
+			group:   group,
+			format:  format,
+			prefix:  item.Prefix,
+			refresh: item.Refresh == nil || *item.Refresh,
+		})
+	}
+	return entries, nil
+}
+
+func (n *nacosAdapter) Init(v *viper.Viper) error {
+
+	opt, err := loadNacosOption(v)
+	if err != nil {
+		return err
+	}
+
+	param, err := opt.ClientParam()
+	if err != nil {
+		return err
+	}
+
+	entries, err := newNacosEntries(opt)
+	if err != nil {
+		return err
+	}
+
+	client, err := n.clientFor(param)
 	if err != nil {
-		return fmt.Errorf("failed to get config from nacos: %w", err)
+		return err
 	}
 
-	if err = mergeConfig(v, content); err != nil {
-		return fmt.Errorf("failed to merge nacos config: %w", err)
+	for _, entry := range entries {
+		n.logger.Info("initializing", zap.String("data_id", entry.dataID), zap.String("group", entry.group), zap.String("namespace", opt.Namespace))
+
+		content, err := client.GetConfig(vo.ConfigParam{
+			DataId: entry.dataID,
+			Group:  entry.group,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get config %s from nacos: %w", entry, err)
+		}
+
+		settings, err := parseSettings(content, entry.format)
+		if err != nil {
+			return fmt.Errorf("failed to parse nacos config %s: %w", entry, err)
+		}
+		entry.settings = nestSettings(entry.prefix, settings)
 	}
 
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.group = opt.Group
+	n.base = cloneSettings(v.AllSettings())
+	n.entries = entries
+	n.applyLocked(v)
+
 	return nil
 }
 
+// clientFor 返回与 param 对应的 SDK 客户端。SDK 客户端无法关闭，
+// 重连重试时复用已创建的客户端，避免每次重试泄漏一组后台协程
+func (n *nacosAdapter) clientFor(param vo.NacosClientParam) (config_client.IConfigClient, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.client != nil && reflect.DeepEqual(n.param, param) {
+		return n.client, nil
+	}
+	client, err := clients.NewConfigClient(param)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nacos client: %w", err)
+	}
+	n.client = client
+	n.param = param
+	return client, nil
+}
+
 func (n *nacosAdapter) Watch(v *viper.Viper, onChange func()) error {
-	sub := v.Sub("config_center.nacos")
-	if sub == nil {
-		return fmt.Errorf("missing config_center.nacos block")
+	n.mu.Lock()
+	client := n.client
+	entries := n.entries
+	n.mu.Unlock()
+
+	if client == nil {
+		return fmt.Errorf("nacos client not initialized")
 	}
 
-	dataID := sub.GetString("data_id")
-	group := sub.GetString("group")
+	for _, entry := range entries {
+		if !entry.refresh {
+			continue
+		}
 
-	return n.client.ListenConfig(vo.ConfigParam{
-		DataId: dataID,
-		Group:  group,
-		OnChange: func(_, _, _, data string) {
-			fmt.Println("[Nacos] Config changed, reloading...")
+		e := entry
+		err := client.ListenConfig(vo.ConfigParam{
+			DataId: e.dataID,
+			Group:  e.group,
+			OnChange: func(_, _, _, data string) {
+				n.logger.Info("config changed, reloading", zap.Stringer("config", e))
 
-			if strings.TrimSpace(data) == "" {
-				fmt.Println("[Nacos] Empty config received, skipping reload.")
-				return
-			}
+				if strings.TrimSpace(data) == "" {
+					n.logger.Warn("empty config received, skipping reload", zap.Stringer("config", e))
+					return
+				}
 
-			if err := mergeConfig(v, data); err != nil {
-				fmt.Println("[Nacos] Failed to merge config:", err)
-				return
-			}
+				settings, err := parseSettings(data, e.format)
+				if err != nil {
+					n.logger.Error("failed to merge config", zap.Stringer("config", e), zap.Error(err))
+					return
+				}
 
-			onChange()
-		},
-	})
+				n.mu.Lock()
+				e.settings = nestSettings(e.prefix, settings)
+				n.applyLocked(v)
+				n.mu.Unlock()
+
+				onChange()
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to listen nacos config %s: %w", e, err)
+		}
+	}
+
+	return nil
 }
 
-func (n *nacosAdapter) Close() {
-	// Nacos client doesn't need explicit close
+// applyLocked 以初始化时的本地配置为底，按声明顺序叠加各 data id 的配置
+func (n *nacosAdapter) applyLocked(v *viper.Viper) {
+	merged := cloneSettings(n.base)
+	for _, entry := range n.entries {
+		merged = deepMerge(merged, entry.settings)
+	}
+	replaceSettings(v, merged)
+}
+
+func (n *nacosAdapter) Layers() []SourceLayer {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	layers := make([]SourceLayer, 0, len(n.entries))
+	for _, entry := range n.entries {
+		layers = append(layers, SourceLayer{
+			Name:     "nacos:" + entry.String(),
+			Settings: cloneSettings(entry.settings),
+		})
+	}
+	return layers
+}
+
+// splitKey 解析 "dataId" 或 "dataId@group" 形式的 key
+func (n *nacosAdapter) splitKey(key string) (string, string) {
+	if i := strings.LastIndex(key, "@"); i > 0 {
+		return key[:i], key[i+1:]
+	}
+	group := n.group
+	if group == "" {
+		group = defaultNacosGroup
+	}
+	return key, group
+}
+
+func (n *nacosAdapter) connectedClient() (config_client.IConfigClient, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.client == nil {
+		return nil, fmt.Errorf("nacos client not initialized")
+	}
+	return n.client, nil
+}
+
+func (n *nacosAdapter) keyFormat(key string) (string, bool) {
+	dataID, group := n.splitKey(key)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, entry := range n.entries {
+		if entry.dataID == dataID && entry.group == group {
+			return entry.format, true
+		}
+	}
+	return "", false
 }
 
-func mergeConfig(v *viper.Viper, content string) error {
-	temp := viper.New()
-	temp.SetConfigType("yaml")
-	if err := temp.ReadConfig(strings.NewReader(content)); err != nil {
-		return fmt.Errorf("failed to parse config: %w", err)
+func (n *nacosAdapter) Get(key string) (string, error) {
+	client, err := n.connectedClient()
+	if err != nil {
+		return "", err
 	}
+	dataID, group := n.splitKey(key)
+	return client.GetConfig(vo.ConfigParam{
+		DataId: dataID,
+		Group:  group,
+	})
+}
 
-	// 强制清除旧配置（viper 不支持直接清空，只能逐个删）
-	for k := range v.AllSettings() {
-		v.Set(k, nil)
+func (n *nacosAdapter) Publish(key, content string) error {
+	client, err := n.connectedClient()
+	if err != nil {
+		return err
+	}
+	dataID, group := n.splitKey(key)
+	format, ok := n.keyFormat(key)
+	if !ok {
+		if format, err = normalizeFormat("", dataID); err != nil {
+			return err
+		}
 	}
 
-	// 覆盖所有字段
-	for k, val := range temp.AllSettings() {
-		v.Set(k, val)
+	ok, err = client.PublishConfig(vo.ConfigParam{
+		DataId:  dataID,
+		Group:   group,
+		Content: content,
+		Type:    vo.ConfigType(format),
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("nacos rejected publishing %s@%s", dataID, group)
 	}
+	return nil
+}
 
+func (n *nacosAdapter) Delete(key string) error {
+	client, err := n.connectedClient()
+	if err != nil {
+		return err
+	}
+	dataID, group := n.splitKey(key)
+	ok, err := client.DeleteConfig(vo.ConfigParam{
+		DataId: dataID,
+		Group:  group,
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("nacos rejected deleting %s@%s", dataID, group)
+	}
 	return nil
 }
+
+func (n *nacosAdapter) Close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.client == nil {
+		return
+	}
+	for _, entry := range n.entries {
+		if !entry.refresh {
+			continue
+		}
+		_ = n.client.CancelListenConfig(vo.ConfigParam{
+			DataId: entry.dataID,
+			Group:  entry.group,
+		})
+	}
+}
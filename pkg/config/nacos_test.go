@@ -0,0 +1,16 @@
+package config
+
+import "testing"
+
+func TestNacosClientParamTLS(t *testing.T) {
+	opt := DefaultNacosClientOption()
+	opt.Host = "nacos.example.com"
+	opt.TLS.Enabled = true
+	param, err := opt.ClientParam()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if param.ServerConfigs[0].Scheme != "https" {
+		t.Errorf("scheme = %q, want https", param.ServerConfigs[0].Scheme)
+	}
+}
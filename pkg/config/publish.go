@@ -0,0 +1,251 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrPublishNotSupported = errors.New("config center does not support publishing")
+	ErrSourceNotConnected  = errors.New("config center is not connected")
+	ErrInvalidContent      = errors.New("invalid config content")
+)
+
+// ConfigPublisher 由支持写入的配置中心实现，key 的格式由各适配器定义，
+// 例如 Nacos 为 "dataId" 或 "dataId@group"
+type ConfigPublisher interface {
+	Get(key string) (string, error)
+	Publish(key, content string) error
+	Delete(key string) error
+}
+
+// 配置变更类型
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// Change 描述单个 key 的变更
+type Change struct {
+	Key  string      `json:"key"`
+	Type string      `json:"type"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// keyFormatter 由能够给出 key 配置格式的适配器实现，例如 Nacos 中已声明 format 的 data id
+type keyFormatter interface {
+	keyFormat(key string) (string, bool)
+}
+
+// PushResult 是一次发布（或预演）的结果
+type PushResult struct {
+	Source    string   `json:"source"`
+	Key       string   `json:"key"`
+	DryRun    bool     `json:"dry_run"`
+	Published bool     `json:"published"`
+	Changes   []Change `json:"changes"`
+}
+
+// FlattenSettings 将嵌套配置展开为以 . 分隔的 key
+func FlattenSettings(settings map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	flattenInto(out, "", settings)
+	return out
+}
+
+func flattenInto(out map[string]interface{}, prefix string, settings map[string]interface{}) {
+	for k, v := range settings {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if child, ok := v.(map[string]interface{}); ok && len(child) > 0 {
+			flattenInto(out, key, child)
+			continue
+		}
+		out[key] = v
+	}
+}
+
+// DiffSettings 按展开后的 key 比较两份配置，结果按 key 排序
+func DiffSettings(old, new map[string]interface{}) []Change {
+	oldFlat := FlattenSettings(old)
+	newFlat := FlattenSettings(new)
+
+	changes := make([]Change, 0)
+	for k, ov := range oldFlat {
+		nv, ok := newFlat[k]
+		if !ok {
+			changes = append(changes, Change{Key: k, Type: ChangeRemoved, Old: ov})
+		} else if !reflect.DeepEqual(ov, nv) {
+			changes = append(changes, Change{Key: k, Type: ChangeModified, Old: ov, New: nv})
+		}
+	}
+	for k, nv := range newFlat {
+		if _, ok := oldFlat[k]; !ok {
+			changes = append(changes, Change{Key: k, Type: ChangeAdded, New: nv})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// redactChanges 返回对敏感 key 的新旧值脱敏后的变更
+func redactChanges(changes []Change) []Change {
+	redacted := make([]Change, len(changes))
+	for i, c := range changes {
+		redacted[i] = Change{
+			Key:  c.Key,
+			Type: c.Type,
+			Old:  RedactValue(c.Key, c.Old),
+			New:  RedactValue(c.Key, c.New),
+		}
+	}
+	return redacted
+}
+
+// publisher 查找可写入的配置来源，name 为空时使用第一个支持写入的来源
+func (cm *ConfigManager) publisher(name string) (string, ConfigPublisher, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	for _, src := range cm.sources {
+		if name != "" && src.name != name {
+			continue
+		}
+		if src.adapter == nil {
+			if name == "" {
+				continue
+			}
+			return "", nil, fmt.Errorf("%w: %s", ErrSourceNotConnected, src.name)
+		}
+		pub, ok := src.adapter.(ConfigPublisher)
+		if !ok {
+			if name == "" {
+				continue
+			}
+			return "", nil, fmt.Errorf("%w: %s", ErrPublishNotSupported, src.name)
+		}
+		return src.name, pub, nil
+	}
+	if name == "" {
+		return "", nil, ErrPublishNotSupported
+	}
+	return "", nil, fmt.Errorf("%w: %s", ErrConfigCenterNotFound, name)
+}
+
+// PushConfig 将配置内容发布到配置来源 source 的 key，返回与当前内容的差异（敏感项已脱敏）；
+// dryRun 为 true 时只计算差异不发布。内容格式见 publishFormat。
+func (cm *ConfigManager) PushConfig(source, key, content string, dryRun bool) (*PushResult, error) {
+	if strings.TrimSpace(key) == "" {
+		return nil, fmt.Errorf("%w: config key must not be empty", ErrInvalidContent)
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("%w: empty content, use DeleteConfig to remove %s", ErrInvalidContent, key)
+	}
+
+	name, pub, err := cm.publisher(source)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := publishFormat(pub, key)
+	if err != nil {
+		return nil, err
+	}
+	newSettings, err := parseSettings(content, format)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s: %v", ErrInvalidContent, key, err)
+	}
+
+	current, err := pub.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current config %s from %s: %w", key, name, err)
+	}
+	oldSettings := map[string]interface{}{}
+	if strings.TrimSpace(current) != "" {
+		if oldSettings, err = parseSettings(current, format); err != nil {
+			return nil, fmt.Errorf("failed to parse current config %s from %s: %w", key, name, err)
+		}
+	}
+
+	result := &PushResult{
+		Source:  name,
+		Key:     key,
+		DryRun:  dryRun,
+		Changes: redactChanges(DiffSettings(oldSettings, newSettings)),
+	}
+	if dryRun || current == content {
+		return result, nil
+	}
+
+	if err = pub.Publish(key, content); err != nil {
+		return nil, fmt.Errorf("failed to publish config %s to %s: %w", key, name, err)
+	}
+	result.Published = true
+	return result, nil
+}
+
+// DeleteConfig 删除配置来源 source 中的 key，dryRun 为 true 时只返回将被移除的配置（敏感项已脱敏）
+func (cm *ConfigManager) DeleteConfig(source, key string, dryRun bool) (*PushResult, error) {
+	if strings.TrimSpace(key) == "" {
+		return nil, fmt.Errorf("%w: config key must not be empty", ErrInvalidContent)
+	}
+
+	name, pub, err := cm.publisher(source)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := pub.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current config %s from %s: %w", key, name, err)
+	}
+
+	oldSettings := map[string]interface{}{}
+	if strings.TrimSpace(current) != "" {
+		format, err := publishFormat(pub, key)
+		if err != nil {
+			return nil, err
+		}
+		// 删除时当前内容无法解析也允许继续
+		if parsed, err := parseSettings(current, format); err == nil {
+			oldSettings = parsed
+		}
+	}
+
+	result := &PushResult{
+		Source:  name,
+		Key:     key,
+		DryRun:  dryRun,
+		Changes: redactChanges(DiffSettings(oldSettings, map[string]interface{}{})),
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	if err = pub.Delete(key); err != nil {
+		return nil, fmt.Errorf("failed to delete config %s from %s: %w", key, name, err)
+	}
+	result.Published = true
+	return result, nil
+}
+
+// publishFormat 优先使用适配器中声明的格式，否则按 key 后缀推断（忽略 @group 后缀）
+func publishFormat(pub ConfigPublisher, key string) (string, error) {
+	if f, ok := pub.(keyFormatter); ok {
+		if format, ok := f.keyFormat(key); ok {
+			return format, nil
+		}
+	}
+	name := key
+	if i := strings.LastIndex(key, "@"); i > 0 {
+		name = key[:i]
+	}
+	return normalizeFormat("", name)
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// memoryCenter 是支持写入的内存配置中心
+type memoryCenter struct {
+	data map[string]string
+}
+
+func (m *memoryCenter) Name() string                         { return "memory" }
+func (m *memoryCenter) Init(_ *viper.Viper) error            { return nil }
+func (m *memoryCenter) Watch(_ *viper.Viper, _ func()) error { return nil }
+func (m *memoryCenter) Close()                               {}
+func (m *memoryCenter) Get(key string) (string, error)       { return m.data[key], nil }
+func (m *memoryCenter) Publish(key, content string) error {
+	m.data[key] = content
+	return nil
+}
+func (m *memoryCenter) Delete(key string) error {
+	delete(m.data, key)
+	return nil
+}
+
+func TestPushConfig(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, "config.yaml")
+	writeFile(t, local, "config_center:\n  snapshot_dir: "+filepath.Join(dir, "snapshots")+"\n  memory: {}\n")
+
+	cm, err := NewConfigManager(Options{ConfigFile: ConfigFile(local)})
+	if err != nil {
+		t.Fatalf("new config manager: %v", err)
+	}
+	defer cm.Close()
+
+	center := &memoryCenter{data: map[string]string{
+		"app.yaml": "http:\n  port: 8080\n  mode: debug\n",
+	}}
+	cm.RegisterAdapter(center)
+	if err = cm.ActivateConfigCenter("memory"); err != nil {
+		t.Fatalf("activate: %v", err)
+	}
+
+	content := "http:\n  port: 9090\nredis:\n  addr: 127.0.0.1:6379\n"
+	result, err := cm.PushConfig("", "app.yaml", content, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	want := []Change{
+		{Key: "http.mode", Type: ChangeRemoved, Old: "debug"},
+		{Key: "http.port", Type: ChangeModified, Old: 8080, New: 9090},
+		{Key: "redis.addr", Type: ChangeAdded, New: "127.0.0.1:6379"},
+	}
+	if len(result.Changes) != len(want) {
+		t.Fatalf("changes = %+v, want %+v", result.Changes, want)
+	}
+	for i, c := range result.Changes {
+		if c != want[i] {
+			t.Errorf("changes[%d] = %+v, want %+v", i, c, want[i])
+		}
+	}
+	if result.Published || center.data["app.yaml"] == content {
+		t.Fatal("dry run must not publish")
+	}
+
+	if result, err = cm.PushConfig("memory", "app.yaml", content, false); err != nil || !result.Published {
+		t.Fatalf("push: %+v, %v", result, err)
+	}
+	if center.data["app.yaml"] != content {
+		t.Errorf("content not published")
+	}
+
+	if _, err = cm.PushConfig("memory", "app.yaml", "http: [", false); !errors.Is(err, ErrInvalidContent) {
+		t.Errorf("invalid content err = %v, want ErrInvalidContent", err)
+	}
+
+	// 预演与删除结果中的敏感项脱敏
+	center.data["db.yaml"] = "db:\n  db_host: 10.0.0.1\n  db_password: old-secret\n"
+	result, err = cm.PushConfig("memory", "db.yaml", "db:\n  db_host: 10.0.0.2\n  db_password: new-secret\n", true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	want = []Change{
+		{Key: "db.db_host", Type: ChangeModified, Old: "10.0.0.1", New: "10.0.0.2"},
+		{Key: "db.db_password", Type: ChangeModified, Old: RedactedValue, New: RedactedValue},
+	}
+	if len(result.Changes) != len(want) || result.Changes[0] != want[0] || result.Changes[1] != want[1] {
+		t.Errorf("changes = %+v, want %+v", result.Changes, want)
+	}
+	if result, err = cm.DeleteConfig("memory", "db.yaml", true); err != nil || len(result.Changes) != 2 || result.Changes[1].Old != RedactedValue {
+		t.Errorf("delete dry run: %+v, %v", result, err)
+	}
+
+	if result, err = cm.DeleteConfig("memory", "app.yaml", false); err != nil || len(result.Changes) != 2 {
+		t.Fatalf("delete: %+v, %v", result, err)
+	}
+	if _, ok := center.data["app.yaml"]; ok {
+		t.Errorf("content not deleted")
+	}
+}
@@ -0,0 +1,446 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 配置项的类型，与 JSON Schema 类型对应；duration 为 time.Duration
+const (
+	nodeObject   = "object"
+	nodeMap      = "map"
+	nodeArray    = "array"
+	nodeString   = "string"
+	nodeInteger  = "integer"
+	nodeNumber   = "number"
+	nodeBoolean  = "boolean"
+	nodeDuration = "duration"
+	nodeAny      = "any"
+)
+
+const durationPattern = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+type registeredOption struct {
+	section string
+	option  interface{}
+}
+
+var (
+	optionsMu sync.RWMutex
+	options   []registeredOption
+)
+
+// RegisterOption 登记配置节点 section（以 . 分隔，例如 "redis" 或 "config_center.myadapter"）
+// 对应的 Option 结构体，用于生成 JSON Schema 与参考配置。
+// option 可以是填充了默认值的实例，字段的 default tag 优先于实例中的值；
+// 内置 starter 在包的 init 中登记，自定义 starter 同样应在 init 中调用。
+func RegisterOption(section string, option interface{}) {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+
+	section = strings.ToLower(strings.Trim(section, "."))
+	for i, o := range options {
+		if o.section == section {
+			options[i].option = option
+			return
+		}
+	}
+	options = append(options, registeredOption{section: section, option: option})
+}
+
+// unregisterOption 移除登记的配置节点，供测试清理全局登记
+func unregisterOption(section string) {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+
+	section = strings.ToLower(strings.Trim(section, "."))
+	for i, o := range options {
+		if o.section == section {
+			options = append(options[:i], options[i+1:]...)
+			return
+		}
+	}
+}
+
+// optionNode 是由 Option 结构体解析出的配置树
+type optionNode struct {
+	kind   string
+	fields []*optionField // object
+	elem   *optionNode    // map 的值、array 的元素
+	def    interface{}    // 默认值
+	hasDef bool
+}
+
+type optionField struct {
+	name string
+	node *optionNode
+}
+
+func (n *optionNode) field(name string) *optionNode {
+	for _, f := range n.fields {
+		if f.name == name {
+			return f.node
+		}
+	}
+	return nil
+}
+
+func (n *optionNode) setField(name string, node *optionNode) {
+	for _, f := range n.fields {
+		if f.name == name {
+			f.node = node
+			return
+		}
+	}
+	n.fields = append(n.fields, &optionField{name: name, node: node})
+}
+
+// optionTree 合并所有已登记的 Option，section 按名称排序
+func optionTree() *optionNode {
+	optionsMu.RLock()
+	registered := make([]registeredOption, len(options))
+	copy(registered, options)
+	optionsMu.RUnlock()
+
+	sort.SliceStable(registered, func(i, j int) bool { return registered[i].section < registered[j].section })
+
+	root := &optionNode{kind: nodeObject}
+	for _, o := range registered {
+		node := buildOptionNode(reflect.TypeOf(o.option), reflect.ValueOf(o.option), "")
+		parts := strings.Split(o.section, ".")
+		parent := root
+		for _, part := range parts[:len(parts)-1] {
+			child := parent.field(part)
+			if child == nil || child.kind != nodeObject {
+				child = &optionNode{kind: nodeObject}
+				parent.setField(part, child)
+			}
+			parent = child
+		}
+
+		name := parts[len(parts)-1]
+		if existing := parent.field(name); existing != nil && existing.kind == nodeObject && node.kind == nodeObject {
+			for _, f := range node.fields {
+				existing.setField(f.name, f.node)
+			}
+			continue
+		}
+		parent.setField(name, node)
+	}
+	return root
+}
+
+func buildOptionNode(t reflect.Type, v reflect.Value, tagDefault string) *optionNode {
+	if t == nil {
+		return &optionNode{kind: nodeAny}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		if v.IsValid() {
+			if v.IsNil() {
+				v = reflect.Value{}
+			} else {
+				v = v.Elem()
+			}
+		}
+	}
+
+	node := &optionNode{}
+	switch {
+	case t == durationType:
+		node.kind = nodeDuration
+	case t.Kind() == reflect.Struct:
+		node.kind = nodeObject
+		addStructFields(node, t, v)
+	case t.Kind() == reflect.Map:
+		node.kind = nodeMap
+		node.elem = buildOptionNode(t.Elem(), reflect.Value{}, "")
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		node.kind = nodeArray
+		node.elem = buildOptionNode(t.Elem(), reflect.Value{}, "")
+	case t.Kind() == reflect.Bool:
+		node.kind = nodeBoolean
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		node.kind = nodeInteger
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		node.kind = nodeNumber
+	case t.Kind() == reflect.String:
+		node.kind = nodeString
+	default:
+		node.kind = nodeAny
+	}
+
+	if tagDefault != "" {
+		node.def, node.hasDef = parseDefaultTag(node.kind, tagDefault), true
+	} else if v.IsValid() && !v.IsZero() {
+		node.def, node.hasDef = valueDefault(node, v)
+	}
+	return node
+}
+
+func addStructFields(node *optionNode, t reflect.Type, v reflect.Value) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("mapstructure")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+
+		var fv reflect.Value
+		if v.IsValid() {
+			fv = v.Field(i)
+		}
+
+		if strings.Contains(opts, "squash") {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+				if fv.IsValid() {
+					if fv.IsNil() {
+						fv = reflect.Value{}
+					} else {
+						fv = fv.Elem()
+					}
+				}
+			}
+			addStructFields(node, ft, fv)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		node.setField(strings.ToLower(name), buildOptionNode(f.Type, fv, f.Tag.Get("default")))
+	}
+}
+
+func parseDefaultTag(kind, tag string) interface{} {
+	switch kind {
+	case nodeBoolean:
+		if b, err := strconv.ParseBool(tag); err == nil {
+			return b
+		}
+	case nodeInteger:
+		if i, err := strconv.ParseInt(tag, 10, 64); err == nil {
+			return i
+		}
+	case nodeNumber:
+		if f, err := strconv.ParseFloat(tag, 64); err == nil {
+			return f
+		}
+	}
+	return tag
+}
+
+// valueDefault 读取实例中的默认值，未导出字段无法 Interface，按类型读取
+func valueDefault(node *optionNode, v reflect.Value) (interface{}, bool) {
+	switch node.kind {
+	case nodeDuration:
+		return formatDuration(time.Duration(v.Int())), true
+	case nodeBoolean:
+		return v.Bool(), true
+	case nodeInteger:
+		if v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64 {
+			return v.Uint(), true
+		}
+		return v.Int(), true
+	case nodeNumber:
+		return v.Float(), true
+	case nodeString:
+		return v.String(), true
+	case nodeArray:
+		if node.elem.kind == nodeObject || node.elem.kind == nodeMap || node.elem.kind == nodeArray {
+			return nil, false
+		}
+		items := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if item, ok := valueDefault(node.elem, v.Index(i)); ok {
+				items = append(items, item)
+			}
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+// JSONSchema 根据已登记的 Option 生成 JSON Schema（draft-07），未知 key 视为无效
+func JSONSchema() ([]byte, error) {
+	schema := nodeSchema(optionTree())
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = "goboot config"
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func nodeSchema(n *optionNode) map[string]interface{} {
+	schema := map[string]interface{}{}
+	switch n.kind {
+	case nodeObject:
+		props := make(map[string]interface{}, len(n.fields))
+		for _, f := range n.fields {
+			props[f.name] = nodeSchema(f.node)
+		}
+		schema["type"] = "object"
+		schema["properties"] = props
+		schema["additionalProperties"] = false
+	case nodeMap:
+		schema["type"] = "object"
+		schema["additionalProperties"] = nodeSchema(n.elem)
+	case nodeArray:
+		schema["type"] = "array"
+		schema["items"] = nodeSchema(n.elem)
+	case nodeDuration:
+		schema["type"] = []string{"string", "integer"}
+		schema["pattern"] = durationPattern
+	case nodeAny:
+	default:
+		schema["type"] = n.kind
+	}
+	if n.hasDef {
+		schema["default"] = n.def
+	}
+	return schema
+}
+
+// ReferenceConfig 根据已登记的 Option 生成带注释的参考 config.yaml，值为各项默认值
+func ReferenceConfig() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# goboot 参考配置，由 `goboot config reference` 根据各模块 Option 生成，值为默认值\n")
+	for _, f := range optionTree().fields {
+		buf.WriteString("\n")
+		writeReference(&buf, 0, "", f.name, f.node)
+	}
+	return buf.Bytes()
+}
+
+// writeReference 输出一个配置项，prefix 用于输出注释掉的示例（数组元素）
+func writeReference(buf *bytes.Buffer, indent int, prefix, name string, n *optionNode) {
+	pad := prefix + strings.Repeat("  ", indent)
+	switch n.kind {
+	case nodeObject:
+		fmt.Fprintf(buf, "%s%s:\n", pad, name)
+		for _, f := range n.fields {
+			writeReference(buf, indent+1, prefix, f.name, f.node)
+		}
+	case nodeArray:
+		if n.elem.kind == nodeObject {
+			fmt.Fprintf(buf, "%s%s: []  # array\n", pad, name)
+			writeArrayExample(buf, indent+1, prefix, n.elem)
+			return
+		}
+		fmt.Fprintf(buf, "%s%s: %s  # array\n", pad, name, yamlScalar(n.def, "[]"))
+	case nodeMap:
+		fmt.Fprintf(buf, "%s%s: {}  # map\n", pad, name)
+	default:
+		fmt.Fprintf(buf, "%s%s: %s  # %s\n", pad, name, yamlScalar(n.def, zeroScalar(n.kind)), n.kind)
+	}
+}
+
+// writeArrayExample 以注释输出数组元素的字段
+func writeArrayExample(buf *bytes.Buffer, indent int, prefix string, elem *optionNode) {
+	commented := prefix
+	if commented == "" {
+		commented = "# "
+	}
+	for i, f := range elem.fields {
+		var line bytes.Buffer
+		writeReference(&line, 0, "", f.name, f.node)
+		for j, l := range strings.Split(strings.TrimRight(line.String(), "\n"), "\n") {
+			lead := "  "
+			if i == 0 && j == 0 {
+				lead = "- "
+			}
+			fmt.Fprintf(buf, "%s%s%s%s\n", commented, strings.Repeat("  ", indent), lead, l)
+		}
+	}
+}
+
+// formatDuration 去掉 time.Duration.String 末尾多余的零单位，例如 5m0s → 5m
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+func zeroScalar(kind string) string {
+	switch kind {
+	case nodeBoolean:
+		return "false"
+	case nodeInteger, nodeNumber:
+		return "0"
+	case nodeDuration:
+		return "0s"
+	case nodeAny:
+		return "null"
+	default:
+		return `""`
+	}
+}
+
+func yamlScalar(v interface{}, zero string) string {
+	if v == nil {
+		return zero
+	}
+	if s, ok := v.(string); ok && s == "" {
+		return zero
+	}
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return zero
+	}
+	text := strings.TrimSpace(string(out))
+	if _, isList := v.([]interface{}); isList {
+		// 列表以 flow 形式输出在同一行
+		items := v.([]interface{})
+		parts := make([]string, 0, len(items))
+		for _, item := range items {
+			part := yamlScalar(item, `""`)
+			// flow 序列中的普通标量不能包含 , [ ] { }
+			if str, ok := item.(string); ok && part == str && strings.ContainsAny(str, ",[]{}") {
+				part = "'" + strings.ReplaceAll(str, "'", "''") + "'"
+			}
+			parts = append(parts, part)
+		}
+		text = "[" + strings.Join(parts, ", ") + "]"
+	}
+	return text
+}
+
+// centerSchema 描述 config_center 节点，仅用于生成 schema 与参考配置
+type centerSchema struct {
+	*centerOption `mapstructure:",squash"`
+	Nacos         *nacosOption      `mapstructure:"nacos"`
+	Etcd          *etcdOption       `mapstructure:"etcd"`
+	Consul        *consulOption     `mapstructure:"consul"`
+	Directory     *directoryOption  `mapstructure:"directory"`
+	HTTP          *httpSourceOption `mapstructure:"http"`
+}
+
+func init() {
+	RegisterOption("config_center", centerSchema{
+		centerOption: defaultCenterOption(),
+		Nacos:        defaultNacosOption(),
+		Etcd:         defaultEtcdOption(),
+		Consul:       defaultConsulOption(),
+		Directory:    defaultDirectoryOption(),
+		HTTP:         defaultHTTPSourceOption(),
+	})
+}
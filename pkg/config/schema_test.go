@@ -0,0 +1,75 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type schemaTestBase struct {
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+type schemaTestOption struct {
+	schemaTestBase `mapstructure:",squash"`
+	Name           string            `mapstructure:"name" default:"demo"`
+	Port           int               `mapstructure:"port"`
+	Tags           []string          `mapstructure:"tags"`
+	Labels         map[string]string `mapstructure:"labels"`
+	Ignored        string            `mapstructure:"-"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	RegisterOption("schema_test.child", &schemaTestOption{
+		schemaTestBase: schemaTestBase{Timeout: 5 * time.Minute},
+		Port:           8080,
+		Tags:           []string{"a", "b"},
+	})
+	t.Cleanup(func() { unregisterOption("schema_test.child") })
+
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+
+	var schema struct {
+		Properties map[string]struct {
+			Properties map[string]struct {
+				AdditionalProperties bool                              `json:"additionalProperties"`
+				Properties           map[string]map[string]interface{} `json:"properties"`
+			} `json:"properties"`
+		} `json:"properties"`
+	}
+	if err = json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("unmarshal schema: %v", err)
+	}
+
+	child := schema.Properties["schema_test"].Properties["child"]
+	if child.AdditionalProperties {
+		t.Errorf("unknown keys should be rejected")
+	}
+	props := child.Properties
+	if props["timeout"]["default"] != "5m" || props["timeout"]["pattern"] != durationPattern {
+		t.Errorf("timeout = %v, want duration with default 5m", props["timeout"])
+	}
+	if props["name"]["default"] != "demo" {
+		t.Errorf("name default = %v, want demo", props["name"]["default"])
+	}
+	if props["port"]["type"] != "integer" || props["port"]["default"] != float64(8080) {
+		t.Errorf("port = %v, want integer default 8080", props["port"])
+	}
+	if props["labels"]["type"] != "object" {
+		t.Errorf("labels = %v, want object", props["labels"])
+	}
+	if _, ok := props["ignored"]; ok {
+		t.Errorf("ignored field should be skipped")
+	}
+
+	ref := string(ReferenceConfig())
+	for _, want := range []string{"schema_test:\n  child:\n", "    timeout: 5m  # duration\n", "    tags: [a, b]  # array\n"} {
+		if !strings.Contains(ref, want) {
+			t.Errorf("reference config missing %q", want)
+		}
+	}
+}
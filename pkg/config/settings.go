@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const defaultFormat = "yaml"
+
+var supportedFormats = map[string]string{
+	"yaml":       "yaml",
+	"yml":        "yaml",
+	"json":       "json",
+	"properties": "properties",
+	"props":      "properties",
+	"toml":       "toml",
+}
+
+// normalizeFormat 返回 viper 可识别的格式名，未指定时按名称后缀推断
+func normalizeFormat(format, name string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+		if f, ok := supportedFormats[ext]; ok {
+			return f, nil
+		}
+		return defaultFormat, nil
+	}
+	if f, ok := supportedFormats[format]; ok {
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported config format: %s", format)
+}
+
+// parseSettings 将配置内容按格式解析为 settings map
+func parseSettings(content, format string) (map[string]interface{}, error) {
+	temp := viper.New()
+	temp.SetConfigType(format)
+	if err := temp.ReadConfig(strings.NewReader(content)); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return temp.AllSettings(), nil
+}
+
+// nestSettings 将 settings 挂载到 prefix（以 . 分隔）之下
+func nestSettings(prefix string, settings map[string]interface{}) map[string]interface{} {
+	prefix = strings.Trim(strings.ToLower(strings.TrimSpace(prefix)), ".")
+	if prefix == "" {
+		return settings
+	}
+	parts := strings.Split(prefix, ".")
+	out := settings
+	for i := len(parts) - 1; i >= 0; i-- {
+		out = map[string]interface{}{parts[i]: out}
+	}
+	return out
+}
+
+// deepMerge 将 src 递归合并进 dst，同名非 map 值以 src 为准
+func deepMerge(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = make(map[string]interface{}, len(src))
+	}
+	for k, sv := range src {
+		srcMap, srcIsMap := sv.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			dst[k] = deepMerge(dstMap, srcMap)
+			continue
+		}
+		dst[k] = cloneValue(sv)
+	}
+	return dst
+}
+
+// replaceSettings 用 settings 整体替换 viper 中的配置
+func replaceSettings(v *viper.Viper, settings map[string]interface{}) {
+	// 强制清除旧配置（viper 不支持直接清空，只能逐个删）
+	for k := range v.AllSettings() {
+		if _, ok := settings[k]; !ok {
+			v.Set(k, nil)
+		}
+	}
+
+	// 覆盖所有字段
+	for k, val := range settings {
+		v.Set(k, val)
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeFormat(t *testing.T) {
+	cases := []struct {
+		format, name, want string
+		wantErr            bool
+	}{
+		{"", "app.yml", "yaml", false},
+		{"", "feature.JSON", "json", false},
+		{"", "app.props", "properties", false},
+		{"", "goboot", "yaml", false}, // 无后缀时默认 yaml
+		{" TOML ", "app.yaml", "toml", false},
+		{"props", "", "properties", false},
+		{"xml", "app.xml", "", true},
+	}
+	for _, tc := range cases {
+		got, err := normalizeFormat(tc.format, tc.name)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("normalizeFormat(%q, %q) = %q, %v; want %q, error %v", tc.format, tc.name, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestNestSettings(t *testing.T) {
+	settings := map[string]interface{}{"enabled": true}
+
+	if got := nestSettings("", settings); !reflect.DeepEqual(got, settings) {
+		t.Errorf("empty prefix = %v", got)
+	}
+	want := map[string]interface{}{
+		"feature": map[string]interface{}{
+			"flags": map[string]interface{}{"enabled": true},
+		},
+	}
+	for _, prefix := range []string{"feature.flags", " .Feature.Flags. "} {
+		if got := nestSettings(prefix, settings); !reflect.DeepEqual(got, want) {
+			t.Errorf("nestSettings(%q) = %v, want %v", prefix, got, want)
+		}
+	}
+}
+
+func TestDeepMerge(t *testing.T) {
+	dst := map[string]interface{}{
+		"http":  map[string]interface{}{"port": 8000, "mode": "release"},
+		"redis": map[string]interface{}{"addr": "shared:6379"},
+		"tags":  []interface{}{"a", "b"},
+		"db":    "sqlite",
+	}
+	srcDB := map[string]interface{}{"driver": "mysql"}
+	src := map[string]interface{}{
+		"http":  map[string]interface{}{"port": 9000},
+		"redis": "disabled",         // 标量替换 map
+		"db":    srcDB,              // map 替换标量
+		"tags":  []interface{}{"c"}, // 列表整体替换，不做合并
+		"cron":  map[string]interface{}{"enabled": true},
+	}
+
+	got := deepMerge(dst, src)
+	want := map[string]interface{}{
+		"http":  map[string]interface{}{"port": 9000, "mode": "release"},
+		"redis": "disabled",
+		"db":    map[string]interface{}{"driver": "mysql"},
+		"tags":  []interface{}{"c"},
+		"cron":  map[string]interface{}{"enabled": true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("deepMerge = %v, want %v", got, want)
+	}
+
+	// 合并结果不与 src 共享可变值
+	srcDB["driver"] = "postgres"
+	if got["db"].(map[string]interface{})["driver"] != "mysql" {
+		t.Error("merged value aliases src")
+	}
+
+	// 按来源顺序合并，后合并的优先
+	merged := deepMerge(nil, map[string]interface{}{"http": map[string]interface{}{"port": 1}})
+	merged = deepMerge(merged, map[string]interface{}{"http": map[string]interface{}{"port": 2}})
+	if merged["http"].(map[string]interface{})["port"] != 2 {
+		t.Errorf("override order = %v", merged)
+	}
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// 配置中心启动失败时的处理策略
+const (
+	FailPolicyFail        = "fail"         // 直接启动失败
+	FailPolicyUseSnapshot = "use_snapshot" // 使用上次成功拉取的本地快照，无快照时退回本地配置
+	FailPolicyUseLocal    = "use_local"    // 仅使用本地配置
+)
+
+// 配置来源的健康状态
+const (
+	SourceStatusUp    = "up"    // 已连接，配置为最新
+	SourceStatusStale = "stale" // 未连接，正在使用本地快照
+	SourceStatusLocal = "local" // 未连接，仅使用本地配置
+)
+
+type centerOption struct {
+	FailPolicy        string                   `mapstructure:"fail_policy"`
+	SnapshotDir       string                   `mapstructure:"snapshot_dir"`
+	ReconnectInterval time.Duration            `mapstructure:"reconnect_interval"`
+	Sources           []map[string]interface{} `mapstructure:"sources"` // 同时启用的多个配置来源，按声明顺序合并
+}
+
+func defaultCenterOption() *centerOption {
+	return &centerOption{
+		FailPolicy:        FailPolicyUseSnapshot,
+		SnapshotDir:       "./cache/config_center",
+		ReconnectInterval: 30 * time.Second,
+	}
+}
+
+func loadCenterOption(v *viper.Viper) (*centerOption, error) {
+	opt := defaultCenterOption()
+	if sub := v.Sub("config_center"); sub != nil {
+		if err := sub.Unmarshal(opt); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config_center options: %w", err)
+		}
+	}
+	if err := validateFailPolicy(opt.FailPolicy); err != nil {
+		return nil, err
+	}
+	return opt, nil
+}
+
+func validateFailPolicy(policy string) error {
+	switch policy {
+	case FailPolicyFail, FailPolicyUseSnapshot, FailPolicyUseLocal:
+		return nil
+	default:
+		return fmt.Errorf("invalid config_center fail_policy: %s", policy)
+	}
+}
+
+// SourceLayer 是配置中心贡献的一组命名配置，例如 Nacos 的一个 data id
+type SourceLayer struct {
+	Name     string
+	Settings map[string]interface{}
+}
+
+// LayeredConfigCenter 由能够导出自身配置内容的配置中心实现，用于持久化本地快照
+type LayeredConfigCenter interface {
+	Layers() []SourceLayer
+}
+
+// SourceHealth 描述一个配置来源的当前状态
+type SourceHealth struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Status    string    `json:"status"`
+	LastSync  time.Time `json:"last_sync"`
+	LastError string    `json:"last_error,omitempty"`
+	Snapshot  string    `json:"snapshot,omitempty"`
+}
+
+type healthRegistry struct {
+	mu      sync.RWMutex
+	sources map[string]*SourceHealth
+}
+
+func newHealthRegistry() *healthRegistry {
+	return &healthRegistry{sources: make(map[string]*SourceHealth)}
+}
+
+func (h *healthRegistry) update(name string, fn func(s *SourceHealth)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.sources[name]
+	if !ok {
+		s = &SourceHealth{Name: name}
+		h.sources[name] = s
+	}
+	fn(s)
+}
+
+func (h *healthRegistry) remove(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.sources, name)
+}
+
+func (h *healthRegistry) list() []SourceHealth {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	out := make([]SourceHealth, 0, len(h.sources))
+	for _, s := range h.sources {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func snapshotPath(dir, name string) string {
+	return filepath.Join(dir, name+".snapshot.yaml")
+}
+
+func layersSettings(layers []SourceLayer) map[string]interface{} {
+	merged := make(map[string]interface{})
+	for _, layer := range layers {
+		merged = deepMerge(merged, layer.Settings)
+	}
+	return merged
+}
+
+// writeSnapshot 原子写入配置中心的快照文件
+func writeSnapshot(path string, settings map[string]interface{}) error {
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot dir: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+func readSnapshot(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseSettings(string(data), "yaml")
+}
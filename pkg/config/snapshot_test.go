@@ -0,0 +1,233 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// flakyAdapter 在 down 为 true 时初始化失败，用于模拟配置中心不可用
+type flakyAdapter struct {
+	mu     sync.Mutex
+	down   bool
+	inits  int
+	closes int
+}
+
+func (f *flakyAdapter) Name() string {
+	return "flaky"
+}
+
+func (f *flakyAdapter) Init(v *viper.Viper) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inits++
+	if f.down {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (f *flakyAdapter) Watch(v *viper.Viper, onChange func()) error {
+	return nil
+}
+
+func (f *flakyAdapter) Layers() []SourceLayer {
+	return []SourceLayer{{Name: "flaky", Settings: map[string]interface{}{
+		"feature": map[string]interface{}{"from": "center"},
+	}}}
+}
+
+func (f *flakyAdapter) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closes++
+}
+
+func (f *flakyAdapter) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+func (f *flakyAdapter) counts() (int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.inits, f.closes
+}
+
+// newFlakyManager 创建只有本地配置的 ConfigManager，并挂载一个 flaky 来源
+func newFlakyManager(t *testing.T, policy string) (*ConfigManager, *configSource, *flakyAdapter, *int) {
+	t.Helper()
+	dir := t.TempDir()
+	local := filepath.Join(dir, "config.yaml")
+	writeFile(t, local, `feature:
+  from: local
+config_center:
+  snapshot_dir: `+filepath.Join(dir, "snapshots")+`
+  reconnect_interval: 20ms
+`)
+
+	cm, err := NewConfigManager(NewOptions(local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cm.Close)
+
+	adapter := &flakyAdapter{down: true}
+	created := 0
+	cm.RegisterAdapterFactory("flaky", func() ConfigCenter {
+		created++
+		return adapter
+	})
+
+	src := &configSource{name: "flaky", typ: "flaky", failPolicy: policy}
+	cm.mu.Lock()
+	cm.sources = append(cm.sources, src)
+	cm.mu.Unlock()
+	return cm, src, adapter, &created
+}
+
+func (cm *ConfigManager) startSource(src *configSource) error {
+	if err := cm.activateSource(src); err != nil {
+		if err = cm.handleSourceFailure(src, err); err != nil {
+			return err
+		}
+	}
+	cm.mu.Lock()
+	cm.composeLocked()
+	cm.mu.Unlock()
+	return nil
+}
+
+func (cm *ConfigManager) sourceHealth(name string) SourceHealth {
+	for _, h := range cm.Health() {
+		if h.Name == name {
+			return h
+		}
+	}
+	return SourceHealth{}
+}
+
+func (cm *ConfigManager) getString(key string) string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.v.GetString(key)
+}
+
+func TestFailPolicyFail(t *testing.T) {
+	cm, src, adapter, _ := newFlakyManager(t, FailPolicyFail)
+
+	if err := cm.startSource(src); err == nil {
+		t.Fatal("expected error with fail_policy fail")
+	}
+	if _, closes := adapter.counts(); closes != 1 {
+		t.Errorf("closes = %d, want failed init to be closed", closes)
+	}
+}
+
+func TestFailPolicyUseLocal(t *testing.T) {
+	cm, src, _, _ := newFlakyManager(t, FailPolicyUseLocal)
+
+	// 即使存在快照，use_local 也只使用本地配置
+	if err := writeSnapshot(snapshotPath(cm.centerOpt.SnapshotDir, src.name), map[string]interface{}{
+		"feature": map[string]interface{}{"from": "snapshot"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.startSource(src); err != nil {
+		t.Fatal(err)
+	}
+	if h := cm.sourceHealth(src.name); h.Status != SourceStatusLocal || h.LastError == "" {
+		t.Errorf("health = %+v, want local with last error", h)
+	}
+	if got := cm.getString("feature.from"); got != "local" {
+		t.Errorf("feature.from = %q, want local", got)
+	}
+}
+
+func TestFailPolicyUseSnapshot(t *testing.T) {
+	cm, src, _, _ := newFlakyManager(t, FailPolicyUseSnapshot)
+
+	path := snapshotPath(cm.centerOpt.SnapshotDir, src.name)
+	if err := writeSnapshot(path, map[string]interface{}{
+		"feature": map[string]interface{}{"from": "snapshot"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.startSource(src); err != nil {
+		t.Fatal(err)
+	}
+	if h := cm.sourceHealth(src.name); h.Status != SourceStatusStale || h.Snapshot != path {
+		t.Errorf("health = %+v, want stale from %s", h, path)
+	}
+	if got := cm.getString("feature.from"); got != "snapshot" {
+		t.Errorf("feature.from = %q, want snapshot", got)
+	}
+	if !cm.Stale() {
+		t.Error("Stale() = false while running on a snapshot")
+	}
+}
+
+func TestFailPolicyUseSnapshotWithoutSnapshot(t *testing.T) {
+	cm, src, _, _ := newFlakyManager(t, FailPolicyUseSnapshot)
+
+	if err := cm.startSource(src); err != nil {
+		t.Fatal(err)
+	}
+	if h := cm.sourceHealth(src.name); h.Status != SourceStatusLocal {
+		t.Errorf("health = %+v, want local when no snapshot exists", h)
+	}
+	if got := cm.getString("feature.from"); got != "local" {
+		t.Errorf("feature.from = %q, want local", got)
+	}
+}
+
+func TestReconnectReusesAdapter(t *testing.T) {
+	cm, src, adapter, created := newFlakyManager(t, FailPolicyUseLocal)
+
+	if err := cm.startSource(src); err != nil {
+		t.Fatal(err)
+	}
+
+	// 等待几次失败的重连后恢复
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		if inits, _ := adapter.counts(); inits >= 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("reconnect not retried")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	adapter.setDown(false)
+
+	for cm.sourceHealth(src.name).Status != SourceStatusUp {
+		if time.Now().After(deadline) {
+			t.Fatal("source not reconnected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for cm.getString("feature.from") != "center" {
+		if time.Now().After(deadline) {
+			t.Fatalf("feature.from = %q, want center", cm.getString("feature.from"))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if *created != 1 {
+		t.Errorf("adapter created %d times, want 1 across retries", *created)
+	}
+	inits, closes := adapter.counts()
+	if closes != inits-1 {
+		t.Errorf("inits = %d, closes = %d, want every failed init closed", inits, closes)
+	}
+	if h := cm.sourceHealth(src.name); h.Snapshot == "" {
+		t.Errorf("health = %+v, want snapshot written after reconnect", h)
+	}
+}
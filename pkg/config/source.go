@@ -0,0 +1,318 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// configSource 是一个已启用的配置来源。每个来源在独立的 viper 上初始化与监听，
+// 由 ConfigManager 以本地配置为底、按声明顺序叠加各来源的配置。
+type configSource struct {
+	name       string
+	typ        string
+	failPolicy string
+	conf       map[string]interface{} // 适配器参数，挂载为 config_center.<type>
+	adapter    ConfigCenter
+	candidate  ConfigCenter           // 尚未成功初始化的适配器，重连时复用，避免每次重试创建新客户端
+	layers     []SourceLayer          // 该来源贡献的各组配置，用于追溯 key 的出处
+	settings   map[string]interface{} // layers 合并后的配置
+}
+
+func (src *configSource) setLayers(layers []SourceLayer) {
+	src.layers = layers
+	src.settings = layersSettings(layers)
+}
+
+// buildSources 解析 config_center.sources；未配置时退化为单一配置中心
+func (cm *ConfigManager) buildSources(opt *centerOption) ([]*configSource, error) {
+	if len(opt.Sources) == 0 {
+		name := cm.centerName()
+		if name == "" {
+			return nil, nil
+		}
+		return []*configSource{cm.legacySource(name)}, nil
+	}
+
+	sources := make([]*configSource, 0, len(opt.Sources))
+	seen := make(map[string]bool, len(opt.Sources))
+	for i, item := range opt.Sources {
+		conf := cloneSettings(item)
+		typ, _ := conf["type"].(string)
+		name, _ := conf["name"].(string)
+		failPolicy, _ := conf["fail_policy"].(string)
+		delete(conf, "type")
+		delete(conf, "name")
+		delete(conf, "fail_policy")
+
+		if typ == "" {
+			return nil, fmt.Errorf("config_center.sources[%d]: missing type", i)
+		}
+		if _, err := cm.newAdapter(typ); err != nil {
+			return nil, fmt.Errorf("config_center.sources[%d]: %w: %s", i, err, typ)
+		}
+		if name == "" {
+			name = typ
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("config_center.sources[%d]: duplicate source name %s", i, name)
+		}
+		seen[name] = true
+
+		if failPolicy == "" {
+			failPolicy = opt.FailPolicy
+		}
+		if err := validateFailPolicy(failPolicy); err != nil {
+			return nil, fmt.Errorf("config_center.sources[%d]: %w", i, err)
+		}
+
+		sources = append(sources, &configSource{
+			name:       name,
+			typ:        typ,
+			failPolicy: failPolicy,
+			conf:       conf,
+		})
+	}
+	return sources, nil
+}
+
+func (cm *ConfigManager) legacySource(name string) *configSource {
+	conf := map[string]interface{}{}
+	if centers, ok := cm.localSettings["config_center"].(map[string]interface{}); ok {
+		if c, ok := centers[name].(map[string]interface{}); ok {
+			conf = cloneSettings(c)
+		}
+	}
+	failPolicy := FailPolicyUseSnapshot
+	if cm.centerOpt != nil {
+		failPolicy = cm.centerOpt.FailPolicy
+	}
+	return &configSource{
+		name:       name,
+		typ:        name,
+		failPolicy: failPolicy,
+		conf:       conf,
+	}
+}
+
+// newAdapter 优先使用 RegisterAdapter 注册的实例，其次使用工厂创建新实例
+func (cm *ConfigManager) newAdapter(typ string) (ConfigCenter, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if adapter, ok := cm.adapters[typ]; ok {
+		return adapter, nil
+	}
+	if factory, ok := cm.factories[typ]; ok {
+		return factory(), nil
+	}
+	return nil, ErrConfigCenterNotFound
+}
+
+// activateSource 初始化并监听一个配置来源，成功后记录其贡献的配置
+func (cm *ConfigManager) activateSource(src *configSource) error {
+	cm.mu.Lock()
+	adapter := src.candidate
+	cm.mu.Unlock()
+	if adapter == nil {
+		var err error
+		if adapter, err = cm.newAdapter(src.typ); err != nil {
+			return err
+		}
+		cm.mu.Lock()
+		src.candidate = adapter
+		cm.mu.Unlock()
+	}
+
+	if aware, ok := adapter.(LoggerAware); ok {
+		aware.SetLogger(cm.logger.Named(src.typ).With(zap.String("source", src.name)))
+	}
+
+	scratch := viper.New()
+	scratch.Set("config_center", map[string]interface{}{src.typ: cloneSettings(src.conf)})
+
+	// 初始化失败时释放 Init 过程中启动的资源，下次重试复用同一适配器
+	if err := adapter.Init(scratch); err != nil {
+		adapter.Close()
+		return fmt.Errorf("failed to init config center: %w", err)
+	}
+
+	if err := adapter.Watch(scratch, func() {
+		cm.onSourceChange(src, adapter, scratch)
+	}); err != nil {
+		adapter.Close()
+		return fmt.Errorf("failed to watch config center: %w", err)
+	}
+
+	cm.mu.Lock()
+	src.adapter = adapter
+	src.candidate = nil
+	src.setLayers(sourceLayers(src, adapter, scratch))
+	cm.mu.Unlock()
+
+	cm.markSynced(src)
+	return nil
+}
+
+func (cm *ConfigManager) onSourceChange(src *configSource, adapter ConfigCenter, scratch *viper.Viper) {
+	cm.mu.Lock()
+	if src.adapter != adapter {
+		cm.mu.Unlock()
+		return
+	}
+	src.setLayers(sourceLayers(src, adapter, scratch))
+	cm.composeLocked()
+	cm.mu.Unlock()
+
+	cm.markSynced(src)
+	cm.fireReload(src.name)
+}
+
+// sourceLayers 返回来源贡献的配置，不含其自身的 config_center 参数
+func sourceLayers(src *configSource, adapter ConfigCenter, scratch *viper.Viper) []SourceLayer {
+	if layered, ok := adapter.(LayeredConfigCenter); ok {
+		return layered.Layers()
+	}
+	settings := scratch.AllSettings()
+	delete(settings, "config_center")
+	return []SourceLayer{{Name: src.typ + ":" + src.name, Settings: settings}}
+}
+
+// composeLocked 以本地配置（含 profile）为底，按声明顺序叠加各来源的配置，后声明的优先，
+// 最后叠加环境变量
+func (cm *ConfigManager) composeLocked() {
+	merged := cloneSettings(cm.localSettings)
+	for _, src := range cm.sources {
+		merged = deepMerge(merged, src.settings)
+	}
+	cm.envLayers = envLayers(merged)
+	merged = deepMerge(merged, layersSettings(cm.envLayers))
+	replaceSettings(cm.v, merged)
+}
+
+// handleSourceFailure 按 fail_policy 处理配置来源不可用，非 fail 策略下后台持续重连
+func (cm *ConfigManager) handleSourceFailure(src *configSource, cause error) error {
+	if src.failPolicy == FailPolicyFail {
+		return fmt.Errorf("config center %s unavailable: %w", src.name, cause)
+	}
+
+	status := SourceStatusLocal
+	snapshot := ""
+	if src.failPolicy == FailPolicyUseSnapshot {
+		path := snapshotPath(cm.centerOpt.SnapshotDir, src.name)
+		settings, err := readSnapshot(path)
+		if err != nil {
+			cm.logger.Warn("no usable snapshot for config center", zap.String("source", src.name), zap.Error(err))
+		} else {
+			cm.mu.Lock()
+			src.setLayers([]SourceLayer{{Name: "snapshot:" + src.name, Settings: settings}})
+			cm.mu.Unlock()
+			status = SourceStatusStale
+			snapshot = path
+		}
+	}
+
+	cm.health.update(src.name, func(s *SourceHealth) {
+		s.Type = src.typ
+		s.Status = status
+		s.LastError = cause.Error()
+		s.Snapshot = snapshot
+	})
+
+	if status == SourceStatusStale {
+		cm.logger.Warn("config center unavailable, running on stale config from snapshot",
+			zap.String("source", src.name), zap.String("snapshot", snapshot), zap.Error(cause))
+	} else {
+		cm.logger.Warn("config center unavailable, running on local config only",
+			zap.String("source", src.name), zap.Error(cause))
+	}
+
+	go cm.reconnect(src)
+	return nil
+}
+
+func (cm *ConfigManager) reconnect(src *configSource) {
+	ticker := time.NewTicker(cm.centerOpt.ReconnectInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-cm.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		if !cm.hasSource(src) {
+			return
+		}
+		if err := cm.activateSource(src); err != nil {
+			cm.health.update(src.name, func(s *SourceHealth) {
+				s.LastError = err.Error()
+			})
+			cm.logger.Warn("reconnect to config center failed", zap.String("source", src.name), zap.Error(err))
+			continue
+		}
+
+		cm.mu.Lock()
+		cm.composeLocked()
+		cm.mu.Unlock()
+
+		cm.logger.Info("reconnected to config center, leaving stale mode", zap.String("source", src.name))
+		cm.fireReload(src.name)
+		return
+	}
+}
+
+// markSynced 记录配置来源拉取成功，并持久化快照
+func (cm *ConfigManager) markSynced(src *configSource) {
+	cm.mu.RLock()
+	adapter := src.adapter
+	cm.mu.RUnlock()
+
+	snapshot := ""
+	if layered, ok := adapter.(LayeredConfigCenter); ok && cm.centerOpt != nil && cm.centerOpt.SnapshotDir != "" {
+		path := snapshotPath(cm.centerOpt.SnapshotDir, src.name)
+		if err := writeSnapshot(path, layersSettings(layered.Layers())); err != nil {
+			cm.logger.Warn("failed to write config center snapshot", zap.String("source", src.name), zap.Error(err))
+		} else {
+			snapshot = path
+		}
+	}
+
+	cm.health.update(src.name, func(s *SourceHealth) {
+		s.Type = src.typ
+		s.Status = SourceStatusUp
+		s.LastSync = time.Now()
+		s.LastError = ""
+		s.Snapshot = snapshot
+	})
+}
+
+// hasSource 判断来源是否仍在使用，ActivateConfigCenter 切换后旧来源不再重连
+func (cm *ConfigManager) hasSource(src *configSource) bool {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	for _, s := range cm.sources {
+		if s == src {
+			return true
+		}
+	}
+	return false
+}
+
+func (cm *ConfigManager) closeSourcesLocked() {
+	for _, src := range cm.sources {
+		if src.adapter != nil {
+			src.adapter.Close()
+			src.adapter = nil
+		}
+		if src.candidate != nil {
+			src.candidate.Close()
+			src.candidate = nil
+		}
+		cm.health.remove(src.name)
+	}
+	cm.sources = nil
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestMultipleSources(t *testing.T) {
+	dir := t.TempDir()
+	shared := filepath.Join(dir, "shared")
+	app := filepath.Join(dir, "app")
+	for _, d := range []string{shared, app} {
+		if err := os.Mkdir(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(shared, "base.yaml"), "http:\n  port: 8000\n  mode: release\nredis:\n  addr: shared:6379\n")
+	writeFile(t, filepath.Join(app, "app.yaml"), "http:\n  port: 9000\n")
+
+	local := filepath.Join(dir, "config.yaml")
+	writeFile(t, local, `app:
+  name: demo
+http:
+  port: 7000
+  host: 0.0.0.0
+config_center:
+  snapshot_dir: `+filepath.Join(dir, "snapshots")+`
+  reconnect_interval: 50ms
+  sources:
+    - type: directory
+      name: shared
+      path: `+shared+`
+      poll_interval: 50ms
+    - type: directory
+      name: app
+      path: `+app+`
+      poll_interval: 50ms
+    - type: http
+      name: missing
+      fail_policy: use_local
+      url: http://127.0.0.1:1/config.yaml
+`)
+
+	cm, err := NewConfigManager(NewOptions(local))
+	if err != nil {
+		t.Fatalf("new config manager: %v", err)
+	}
+	defer cm.Close()
+
+	// GetViper 返回的实例会被后台刷新，这里在锁内复制一份再断言
+	cm.mu.RLock()
+	v := viper.New()
+	for k, val := range cloneSettings(cm.v.AllSettings()) {
+		v.Set(k, val)
+	}
+	cm.mu.RUnlock()
+	if got := v.GetInt("http.port"); got != 9000 {
+		t.Errorf("http.port = %d, want 9000", got)
+	}
+	if got := v.GetString("http.mode"); got != "release" {
+		t.Errorf("http.mode = %q, want release", got)
+	}
+	if got := v.GetString("http.host"); got != "0.0.0.0" {
+		t.Errorf("http.host = %q, want 0.0.0.0", got)
+	}
+
+	health := map[string]SourceHealth{}
+	for _, h := range cm.Health() {
+		health[h.Name] = h
+	}
+	if health["shared"].Status != SourceStatusUp || health["app"].Status != SourceStatusUp {
+		t.Errorf("unexpected health: %+v", health)
+	}
+	if health["missing"].Status != SourceStatusLocal || health["missing"].Type != "http" {
+		t.Errorf("missing source health = %+v, want local http", health["missing"])
+	}
+
+	effective := map[string]EffectiveValue{}
+	for _, e := range cm.Effective() {
+		effective[e.Key] = e
+	}
+	if got := effective["http.port"].Source; got != "directory:"+app {
+		t.Errorf("http.port source = %q, want directory:%s", got, app)
+	}
+	if got := effective["http.host"].Source; got != "file:"+local {
+		t.Errorf("http.host source = %q, want file:%s", got, local)
+	}
+
+	reloaded := make(chan *viper.Viper, 10)
+	_ = cm.RegisterReloader("test", ConfigReloaderFunc(func(nv *viper.Viper) error {
+		reloaded <- nv
+		return nil
+	}))
+
+	// 低优先级来源的变更不会覆盖高优先级来源的值
+	writeFile(t, filepath.Join(shared, "base.yaml"), "http:\n  port: 8001\n  mode: debug\n")
+	deadline := time.After(3 * time.Second)
+	for v.GetString("http.mode") != "debug" {
+		select {
+		case v = <-reloaded:
+		case <-deadline:
+			t.Fatal("http.mode not updated")
+		}
+	}
+	if got := v.GetInt("http.port"); got != 9000 {
+		t.Errorf("http.port = %d, want 9000", got)
+	}
+	if v.IsSet("redis.addr") {
+		t.Errorf("redis.addr should be removed with the shared source")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// managerOption 是 config 节点，控制 ConfigManager 自身的行为
+type managerOption struct {
+	// 为 true 时，未被任何已登记 Option 使用的 key 在启动时报错、在重载时告警
+	Strict bool        `mapstructure:"strict"`
+	Audit  auditOption `mapstructure:"audit"`
+	// 叠加在本地配置文件之上的 profile 配置文件，例如 prod 对应 config-prod.yaml；环境变量 GOBOOT_PROFILE 优先
+	Profile string `mapstructure:"profile"`
+}
+
+func init() {
+	RegisterOption("config", managerOption{Audit: *defaultAuditOption()})
+}
+
+// UnknownKey 是未被任何已登记 Option 使用的配置 key
+type UnknownKey struct {
+	Key        string `json:"key"`
+	Suggestion string `json:"suggestion,omitempty"` // 最接近的有效 key
+}
+
+func (u UnknownKey) String() string {
+	if u.Suggestion == "" {
+		return u.Key
+	}
+	return fmt.Sprintf("%s (did you mean %s?)", u.Key, u.Suggestion)
+}
+
+// UnknownKeysError 是 strict 模式下启动失败返回的错误
+type UnknownKeysError struct {
+	Keys []UnknownKey
+}
+
+func (e *UnknownKeysError) Error() string {
+	items := make([]string, 0, len(e.Keys))
+	for _, k := range e.Keys {
+		items = append(items, k.String())
+	}
+	return "unknown config keys: " + strings.Join(items, ", ")
+}
+
+// UnknownKeys 返回当前配置中未被任何已登记 Option（见 RegisterOption）使用的 key
+func (cm *ConfigManager) UnknownKeys() []UnknownKey {
+	cm.mu.RLock()
+	settings := cm.v.AllSettings()
+	cm.mu.RUnlock()
+	return unknownKeys(settings)
+}
+
+func unknownKeys(settings map[string]interface{}) []UnknownKey {
+	tree := optionTree()
+	var known []string
+
+	unknown := make([]UnknownKey, 0)
+	for key := range FlattenSettings(settings) {
+		if tree.accepts(strings.Split(key, ".")) {
+			continue
+		}
+		if known == nil {
+			known = tree.keys("")
+		}
+		unknown = append(unknown, UnknownKey{Key: key, Suggestion: suggestKey(key, known)})
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i].Key < unknown[j].Key })
+	return unknown
+}
+
+// checkStrict 在 strict 模式下校验配置，存在未知 key 时返回 UnknownKeysError
+func (cm *ConfigManager) checkStrict() error {
+	if !cm.GetViper().GetBool("config.strict") {
+		return nil
+	}
+	if unknown := cm.UnknownKeys(); len(unknown) > 0 {
+		return &UnknownKeysError{Keys: unknown}
+	}
+	return nil
+}
+
+// accepts 判断 key 路径是否被配置树使用，map 与 array 节点接受其下任意内容
+func (n *optionNode) accepts(path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+	switch n.kind {
+	case nodeMap, nodeArray, nodeAny:
+		return true
+	case nodeObject:
+		child := n.field(path[0])
+		return child != nil && child.accepts(path[1:])
+	default:
+		return false
+	}
+}
+
+// keys 返回配置树中所有叶子 key
+func (n *optionNode) keys(prefix string) []string {
+	if n.kind != nodeObject {
+		return []string{prefix}
+	}
+	out := make([]string, 0, len(n.fields))
+	for _, f := range n.fields {
+		key := f.name
+		if prefix != "" {
+			key = prefix + "." + f.name
+		}
+		out = append(out, f.node.keys(key)...)
+	}
+	return out
+}
+
+// suggestKey 返回与 key 编辑距离最近的有效 key。只有拼写相近，或最后一级名称相同
+// （例如放错了节点 cron.location → cron_starter.location）时才给出建议
+func suggestKey(key string, known []string) string {
+	leaf := key[strings.LastIndex(key, ".")+1:]
+	maxDistance := len(key) / 2
+	if maxDistance < 3 {
+		maxDistance = 3
+	}
+
+	best, bestDistance := "", -1
+	for _, k := range known {
+		d := levenshtein(key, k)
+		if d > maxDistance && k[strings.LastIndex(k, ".")+1:] != leaf {
+			continue
+		}
+		if bestDistance < 0 || d < bestDistance {
+			best, bestDistance = k, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type strictTestOption struct {
+	Location    string            `mapstructure:"location"`
+	StopTimeout time.Duration     `mapstructure:"stop_timeout"`
+	Labels      map[string]string `mapstructure:"labels"`
+}
+
+func TestStrictMode(t *testing.T) {
+	RegisterOption("strict_test_cron", strictTestOption{})
+	t.Cleanup(func() { unregisterOption("strict_test_cron") })
+
+	dir := t.TempDir()
+	local := filepath.Join(dir, "config.yaml")
+	writeFile(t, local, `config:
+  strict: true
+strict_test_cron:
+  location: Local
+  stop_timeot: 5s
+  labels:
+    team: infra
+strict_test:
+  location: UTC
+`)
+
+	_, err := NewConfigManager(Options{ConfigFile: ConfigFile(local)})
+	var unknown *UnknownKeysError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("err = %v, want UnknownKeysError", err)
+	}
+
+	want := []UnknownKey{
+		{Key: "strict_test.location", Suggestion: "strict_test_cron.location"},
+		{Key: "strict_test_cron.stop_timeot", Suggestion: "strict_test_cron.stop_timeout"},
+	}
+	if len(unknown.Keys) != len(want) {
+		t.Fatalf("unknown keys = %v, want %v", unknown.Keys, want)
+	}
+	for i, k := range unknown.Keys {
+		if k != want[i] {
+			t.Errorf("unknown[%d] = %v, want %v", i, k, want[i])
+		}
+	}
+
+	// 非 strict 模式下不影响启动
+	writeFile(t, local, "strict_test_cron:\n  stop_timeot: 5s\n")
+	cm, err := NewConfigManager(Options{ConfigFile: ConfigFile(local)})
+	if err != nil {
+		t.Fatalf("non-strict: %v", err)
+	}
+	cm.Close()
+}
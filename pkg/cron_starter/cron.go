@@ -17,19 +17,27 @@ import (
 var ErrCronDisabled = errors.New("cron_starter scheduler is disabled")
 
 type Option struct {
-	Enabled     bool          `mapstructure:"enabled"`
+	Enabled     bool          `mapstructure:"enabled" default:"true"` // 存在 cron_starter 节点时默认启用
 	Location    string        `mapstructure:"location"`
 	WithSeconds bool          `mapstructure:"with_seconds"`
 	StopTimeout time.Duration `mapstructure:"stop_timeout"`
 }
 
-func NewOption(cfg *config.ConfigManager) (*Option, error) {
-	opt := &Option{
-		Enabled:     cfg.GetViper().InConfig("cron_starter"),
+func init() {
+	config.RegisterOption("cron_starter", defaultOption())
+}
+
+func defaultOption() *Option {
+	return &Option{
 		Location:    "Local",
 		WithSeconds: false,
 		StopTimeout: 5 * time.Second,
 	}
+}
+
+func NewOption(cfg *config.ConfigManager) (*Option, error) {
+	opt := defaultOption()
+	opt.Enabled = cfg.GetViper().InConfig("cron_starter")
 
 	v := cfg.GetViper()
 	if cronCfg := v.Sub("cron_starter"); cronCfg != nil {
@@ -49,7 +57,7 @@ type Scheduler struct {
 
 func NewScheduler(logger *zap.Logger, cfg *config.ConfigManager, opt *Option) (*Scheduler, error) {
 	s := &Scheduler{
-		logger: logger,
+		logger: logger.Named("cron"),
 	}
 
 	if err := s.applyConfig(opt); err != nil {
@@ -116,12 +124,8 @@ func (s *Scheduler) stopLocked(timeout time.Duration) {
 }
 
 func (s *Scheduler) ReloadConfig(v *viper.Viper) error {
-	newOpt := &Option{
-		Enabled:     v.InConfig("cron_starter"),
-		Location:    "Local",
-		WithSeconds: false,
-		StopTimeout: 5 * time.Second,
-	}
+	newOpt := defaultOption()
+	newOpt.Enabled = v.InConfig("cron_starter")
 	if cronCfg := v.Sub("cron_starter"); cronCfg != nil {
 		if err := cronCfg.Unmarshal(newOpt); err != nil {
 			return fmt.Errorf("failed to unmarshal cron_starter options: %w", err)
@@ -0,0 +1,318 @@
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/ahrtolia/goboot/pkg/config"
+	"github.com/google/wire"
+	"github.com/nacos-group/nacos-sdk-go/clients"
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrDiscoveryDisabled = errors.New("discovery is disabled")
+	ErrNoInstance        = errors.New("no available instance")
+)
+
+type Option struct {
+	Enabled bool                     `mapstructure:"enabled" default:"true"` // 存在 discovery 节点时默认启用
+	Nacos   config.NacosClientOption `mapstructure:"nacos"`
+
+	ServiceName string            `mapstructure:"service_name"` // 默认使用 app.name
+	Group       string            `mapstructure:"group"`
+	Cluster     string            `mapstructure:"cluster"`
+	IP          string            `mapstructure:"ip"`   // 为空时自动探测本机地址
+	Port        int               `mapstructure:"port"` // 为 0 时使用 http.port
+	Weight      float64           `mapstructure:"weight"`
+	Version     string            `mapstructure:"version"`
+	Zone        string            `mapstructure:"zone"`
+	Metadata    map[string]string `mapstructure:"metadata"`
+	Ephemeral   bool              `mapstructure:"ephemeral"`
+
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
+	LoadBalancer      string        `mapstructure:"load_balancer"` // weighted_random | round_robin
+}
+
+func init() {
+	config.RegisterOption("discovery", defaultOption())
+}
+
+func defaultOption() *Option {
+	return &Option{
+		Nacos:             config.DefaultNacosClientOption(),
+		Group:             "DEFAULT_GROUP",
+		Cluster:           "DEFAULT",
+		Weight:            1,
+		Ephemeral:         true,
+		HeartbeatInterval: 5 * time.Second,
+		LoadBalancer:      BalancerWeightedRandom,
+	}
+}
+
+func NewOption(cfg *config.ConfigManager) (*Option, error) {
+	return LoadOption(cfg.GetViper())
+}
+
+// LoadOption 从 v 的 discovery 节点读取配置，service_name 默认使用 app.name
+func LoadOption(v *viper.Viper) (*Option, error) {
+	opt := defaultOption()
+	opt.Enabled = v.InConfig("discovery")
+	opt.ServiceName = v.GetString("app.name")
+
+	if discoveryCfg := v.Sub("discovery"); discoveryCfg != nil {
+		if err := discoveryCfg.Unmarshal(opt); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal discovery options: %w", err)
+		}
+	}
+	return opt, nil
+}
+
+// Instance 是服务发现返回的一个服务实例
+type Instance struct {
+	ID       string
+	Service  string
+	Cluster  string
+	IP       string
+	Port     int
+	Weight   float64
+	Healthy  bool
+	Metadata map[string]string
+}
+
+// Addr 返回 "ip:port" 形式的地址
+func (i Instance) Addr() string {
+	return net.JoinHostPort(i.IP, fmt.Sprint(i.Port))
+}
+
+type Registry struct {
+	mu         sync.Mutex
+	logger     *zap.Logger
+	opt        *Option
+	client     naming_client.INamingClient
+	registered *vo.RegisterInstanceParam
+	resolver   *Resolver
+}
+
+func NewRegistry(logger *zap.Logger, opt *Option) (*Registry, error) {
+	r := &Registry{
+		logger: logger.Named("discovery"),
+		opt:    opt,
+	}
+
+	if !opt.Enabled {
+		return r, nil
+	}
+
+	param, err := opt.Nacos.ClientParam()
+	if err != nil {
+		return nil, err
+	}
+	if opt.HeartbeatInterval > 0 {
+		param.ClientConfig.BeatInterval = opt.HeartbeatInterval.Milliseconds()
+	}
+
+	client, err := clients.NewNamingClient(param)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nacos naming client: %w", err)
+	}
+
+	r.client = client
+	r.resolver = newResolver(r.logger, client, opt)
+	return r, nil
+}
+
+func (r *Registry) Enabled() bool {
+	return r.client != nil
+}
+
+// Register 注册本实例，addr 为 HTTP 服务监听地址；临时实例的心跳由 Nacos SDK 按 heartbeat_interval 发送
+func (r *Registry) Register(addr string) error {
+	if r.client == nil {
+		return ErrDiscoveryDisabled
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	param, err := r.instanceParam(r.opt, addr)
+	if err != nil {
+		return err
+	}
+	return r.registerLocked(param)
+}
+
+// Update 按重载后的配置与 HTTP 监听地址更新注册信息：地址、权重、元数据等变化时注销旧实例后重新注册，
+// discovery 被关闭时注销。nacos 连接参数、heartbeat_interval 与 load_balancer 在启动时确定，修改后需重启
+func (r *Registry) Update(opt *Option, addr string) error {
+	if r.client == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.registered == nil {
+		return nil
+	}
+	if !opt.Enabled {
+		return r.deregisterLocked()
+	}
+
+	param, err := r.instanceParam(opt, addr)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(*r.registered, param) {
+		return nil
+	}
+	if err = r.deregisterLocked(); err != nil {
+		return err
+	}
+	r.opt = opt
+	return r.registerLocked(param)
+}
+
+// instanceParam 按 opt 与监听地址构建注册参数
+func (r *Registry) instanceParam(opt *Option, addr string) (vo.RegisterInstanceParam, error) {
+	ip, port, err := instanceAddr(opt, addr)
+	if err != nil {
+		return vo.RegisterInstanceParam{}, err
+	}
+
+	metadata := make(map[string]string, len(opt.Metadata)+2)
+	for k, v := range opt.Metadata {
+		metadata[k] = v
+	}
+	if opt.Version != "" {
+		metadata["version"] = opt.Version
+	}
+	if opt.Zone != "" {
+		metadata["zone"] = opt.Zone
+	}
+
+	return vo.RegisterInstanceParam{
+		Ip:          ip,
+		Port:        uint64(port),
+		Weight:      opt.Weight,
+		Enable:      true,
+		Healthy:     true,
+		Metadata:    metadata,
+		ClusterName: opt.Cluster,
+		ServiceName: opt.ServiceName,
+		GroupName:   opt.Group,
+		Ephemeral:   opt.Ephemeral,
+	}, nil
+}
+
+func (r *Registry) registerLocked(param vo.RegisterInstanceParam) error {
+	if _, err := r.client.RegisterInstance(param); err != nil {
+		return fmt.Errorf("failed to register instance: %w", err)
+	}
+	r.registered = &param
+
+	r.logger.Info("service instance registered",
+		zap.String("service", param.ServiceName),
+		zap.String("group", param.GroupName),
+		zap.String("ip", param.Ip),
+		zap.Uint64("port", param.Port))
+	return nil
+}
+
+// Deregister 注销本实例，未注册时直接返回
+func (r *Registry) Deregister() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.client == nil {
+		return nil
+	}
+	return r.deregisterLocked()
+}
+
+func (r *Registry) deregisterLocked() error {
+	if r.registered == nil {
+		return nil
+	}
+
+	param := r.registered
+	if _, err := r.client.DeregisterInstance(vo.DeregisterInstanceParam{
+		Ip:          param.Ip,
+		Port:        param.Port,
+		Cluster:     param.ClusterName,
+		ServiceName: param.ServiceName,
+		GroupName:   param.GroupName,
+		Ephemeral:   param.Ephemeral,
+	}); err != nil {
+		return fmt.Errorf("failed to deregister instance: %w", err)
+	}
+	r.registered = nil
+
+	r.logger.Info("service instance deregistered", zap.String("service", param.ServiceName))
+	return nil
+}
+
+// Resolver 返回用于出站调用的服务解析器，未启用时返回 nil
+func (r *Registry) Resolver() *Resolver {
+	return r.resolver
+}
+
+func (r *Registry) Close() {
+	if r.resolver != nil {
+		r.resolver.close()
+	}
+}
+
+// instanceAddr 计算注册的 ip 与端口，opt 中未指定时使用监听地址
+func instanceAddr(opt *Option, addr string) (string, int, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+
+	port := opt.Port
+	if port == 0 {
+		if _, err = fmt.Sscan(portStr, &port); err != nil {
+			return "", 0, fmt.Errorf("invalid listen port %q: %w", addr, err)
+		}
+	}
+
+	ip := opt.IP
+	if ip == "" {
+		ip = host
+	}
+	if ip == "" || net.ParseIP(ip).IsUnspecified() {
+		ip, err = localIP()
+		if err != nil {
+			return "", 0, err
+		}
+	}
+	return ip, port, nil
+}
+
+// localIP 返回第一个非回环 IPv4 地址
+func localIP() (string, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", fmt.Errorf("failed to detect local ip: %w", err)
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() {
+			continue
+		}
+		if ip4 := ipNet.IP.To4(); ip4 != nil {
+			return ip4.String(), nil
+		}
+	}
+	return "", fmt.Errorf("failed to detect local ip: no non-loopback ipv4 address")
+}
+
+var ProviderSet = wire.NewSet(NewOption, NewRegistry)
@@ -0,0 +1,100 @@
+package discovery
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestInstanceAddr(t *testing.T) {
+	cases := []struct {
+		name     string
+		ip       string
+		port     int
+		addr     string
+		wantIP   string
+		wantPort int
+		wantErr  bool
+	}{
+		{name: "listen address", addr: "10.0.0.1:8000", wantIP: "10.0.0.1", wantPort: 8000},
+		{name: "configured ip", ip: "192.168.1.10", addr: "10.0.0.1:8000", wantIP: "192.168.1.10", wantPort: 8000},
+		{name: "configured port", port: 9000, addr: "10.0.0.1:8000", wantIP: "10.0.0.1", wantPort: 9000},
+		{name: "ipv6", addr: "[::1]:8000", wantIP: "::1", wantPort: 8000},
+		{name: "missing port", addr: "10.0.0.1", wantErr: true},
+		{name: "invalid port", addr: "10.0.0.1:http", wantErr: true},
+	}
+	for _, tc := range cases {
+		opt := defaultOption()
+		opt.IP = tc.ip
+		opt.Port = tc.port
+		ip, port, err := instanceAddr(opt, tc.addr)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: err = %v, want error %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if !tc.wantErr && (ip != tc.wantIP || port != tc.wantPort) {
+			t.Errorf("%s: instanceAddr(%q) = %s:%d, want %s:%d", tc.name, tc.addr, ip, port, tc.wantIP, tc.wantPort)
+		}
+	}
+
+	// 监听所有地址时注册探测到的本机地址
+	if ip, _, err := instanceAddr(defaultOption(), ":8000"); err == nil && (ip == "" || ip == "0.0.0.0") {
+		t.Errorf("instanceAddr(:8000) ip = %q, want detected local ip", ip)
+	}
+}
+
+func TestRegistryUpdate(t *testing.T) {
+	client := newFakeNamingClient()
+	opt := defaultOption()
+	opt.Enabled = true
+	opt.ServiceName = "order"
+	r := &Registry{logger: zap.NewNop(), opt: opt, client: client}
+
+	// 未注册时忽略
+	if err := r.Update(opt, "10.0.0.1:8000"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("10.0.0.1:8000"); err != nil {
+		t.Fatal(err)
+	}
+
+	same := *opt
+	if err := r.Update(&same, "10.0.0.1:8000"); err != nil {
+		t.Fatal(err)
+	}
+
+	// http 端口变化时重新注册
+	if err := r.Update(&same, "10.0.0.1:9000"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 元数据变化时重新注册
+	tagged := same
+	tagged.Metadata = map[string]string{"canary": "true"}
+	if err := r.Update(&tagged, "10.0.0.1:9000"); err != nil {
+		t.Fatal(err)
+	}
+	if r.registered.Metadata["canary"] != "true" {
+		t.Errorf("metadata = %v, want canary", r.registered.Metadata)
+	}
+
+	// 关闭 discovery 时注销
+	disabled := tagged
+	disabled.Enabled = false
+	if err := r.Update(&disabled, "10.0.0.1:9000"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"register 10.0.0.1:8000",
+		"deregister 10.0.0.1:8000",
+		"register 10.0.0.1:9000",
+		"deregister 10.0.0.1:9000",
+		"register 10.0.0.1:9000",
+		"deregister 10.0.0.1:9000",
+	}
+	if got := strings.Join(client.registry, ", "); got != strings.Join(want, ", ") {
+		t.Errorf("calls = %s\nwant %s", got, strings.Join(want, ", "))
+	}
+}
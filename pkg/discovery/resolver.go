@@ -0,0 +1,225 @@
+package discovery
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"go.uber.org/zap"
+)
+
+const (
+	BalancerWeightedRandom = "weighted_random"
+	BalancerRoundRobin     = "round_robin"
+)
+
+type serviceCache struct {
+	mu        sync.RWMutex
+	instances []Instance
+	next      uint64
+	param     *vo.SubscribeParam
+	ready     chan struct{} // 首次拉取与订阅完成后关闭
+	err       error         // 首次拉取或订阅失败的原因，ready 关闭后可读
+}
+
+func (c *serviceCache) set(instances []Instance) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.instances = instances
+}
+
+func (c *serviceCache) list() []Instance {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.instances
+}
+
+// Resolver 基于 Nacos 订阅维护服务实例缓存，并按负载均衡策略挑选实例
+type Resolver struct {
+	mu       sync.Mutex
+	logger   *zap.Logger
+	client   naming_client.INamingClient
+	opt      *Option
+	services map[string]*serviceCache
+}
+
+func newResolver(logger *zap.Logger, client naming_client.INamingClient, opt *Option) *Resolver {
+	return &Resolver{
+		logger:   logger,
+		client:   client,
+		opt:      opt,
+		services: make(map[string]*serviceCache),
+	}
+}
+
+// Instances 返回服务的健康实例，首次调用时拉取并订阅变更
+func (r *Resolver) Instances(service string) ([]Instance, error) {
+	cache, err := r.cache(service)
+	if err != nil {
+		return nil, err
+	}
+	return cache.list(), nil
+}
+
+// Resolve 按负载均衡策略挑选一个健康实例
+func (r *Resolver) Resolve(service string) (*Instance, error) {
+	cache, err := r.cache(service)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := cache.list()
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoInstance, service)
+	}
+
+	var picked Instance
+	switch r.opt.LoadBalancer {
+	case BalancerRoundRobin:
+		idx := atomic.AddUint64(&cache.next, 1)
+		picked = instances[int((idx-1)%uint64(len(instances)))]
+	default:
+		picked = pickWeighted(instances)
+	}
+	return &picked, nil
+}
+
+// cache 返回服务的实例缓存。拉取与订阅不持有 r.mu，不同服务的首次解析互不阻塞，
+// 同一服务的并发调用等待首次拉取的结果，失败后下次调用重新拉取
+func (r *Resolver) cache(service string) (*serviceCache, error) {
+	r.mu.Lock()
+	cache, ok := r.services[service]
+	if !ok {
+		cache = &serviceCache{ready: make(chan struct{})}
+		r.services[service] = cache
+	}
+	r.mu.Unlock()
+
+	if !ok {
+		cache.err = r.subscribe(service, cache)
+		if cache.err != nil {
+			r.mu.Lock()
+			if r.services[service] == cache {
+				delete(r.services, service)
+			}
+			r.mu.Unlock()
+		}
+		close(cache.ready)
+	}
+
+	<-cache.ready
+	if cache.err != nil {
+		return nil, cache.err
+	}
+	return cache, nil
+}
+
+func (r *Resolver) subscribe(service string, cache *serviceCache) error {
+	found, err := r.client.SelectInstances(vo.SelectInstancesParam{
+		ServiceName: service,
+		GroupName:   r.opt.Group,
+		HealthyOnly: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to select instances of %s: %w", service, err)
+	}
+	cache.set(fromInstances(found))
+
+	cache.param = &vo.SubscribeParam{
+		ServiceName: service,
+		GroupName:   r.opt.Group,
+		SubscribeCallback: func(services []model.SubscribeService, err error) {
+			if err != nil {
+				r.logger.Warn("discovery subscribe callback failed", zap.String("service", service), zap.Error(err))
+				return
+			}
+			cache.set(fromSubscribeServices(services))
+			r.logger.Debug("discovery instances updated", zap.String("service", service), zap.Int("count", len(cache.list())))
+		},
+	}
+	if err = r.client.Subscribe(cache.param); err != nil {
+		return fmt.Errorf("failed to subscribe %s: %w", service, err)
+	}
+	return nil
+}
+
+func (r *Resolver) close() {
+	r.mu.Lock()
+	caches := make([]*serviceCache, 0, len(r.services))
+	for name, cache := range r.services {
+		caches = append(caches, cache)
+		delete(r.services, name)
+	}
+	r.mu.Unlock()
+
+	// 等待进行中的首次订阅完成后再取消
+	for _, cache := range caches {
+		<-cache.ready
+		if cache.err == nil {
+			_ = r.client.Unsubscribe(cache.param)
+		}
+	}
+}
+
+func pickWeighted(instances []Instance) Instance {
+	total := 0.0
+	for _, ins := range instances {
+		total += ins.Weight
+	}
+	if total <= 0 {
+		return instances[rand.Intn(len(instances))]
+	}
+
+	n := rand.Float64() * total
+	for _, ins := range instances {
+		n -= ins.Weight
+		if n < 0 {
+			return ins
+		}
+	}
+	return instances[len(instances)-1]
+}
+
+func fromInstances(in []model.Instance) []Instance {
+	out := make([]Instance, 0, len(in))
+	for _, ins := range in {
+		if !ins.Enable || !ins.Healthy {
+			continue
+		}
+		out = append(out, Instance{
+			ID:       ins.InstanceId,
+			Service:  ins.ServiceName,
+			Cluster:  ins.ClusterName,
+			IP:       ins.Ip,
+			Port:     int(ins.Port),
+			Weight:   ins.Weight,
+			Healthy:  ins.Healthy,
+			Metadata: ins.Metadata,
+		})
+	}
+	return out
+}
+
+func fromSubscribeServices(in []model.SubscribeService) []Instance {
+	out := make([]Instance, 0, len(in))
+	for _, ins := range in {
+		if !ins.Enable || !ins.Healthy {
+			continue
+		}
+		out = append(out, Instance{
+			ID:       ins.InstanceId,
+			Service:  ins.ServiceName,
+			Cluster:  ins.ClusterName,
+			IP:       ins.Ip,
+			Port:     int(ins.Port),
+			Weight:   ins.Weight,
+			Healthy:  ins.Healthy,
+			Metadata: ins.Metadata,
+		})
+	}
+	return out
+}
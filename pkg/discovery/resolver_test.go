@@ -0,0 +1,219 @@
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"go.uber.org/zap"
+)
+
+// fakeNamingClient 只实现 Resolver 与 Registry 用到的方法，其余方法调用时 panic
+type fakeNamingClient struct {
+	naming_client.INamingClient
+
+	mu           sync.Mutex
+	instances    map[string][]model.Instance
+	selectErr    error
+	block        map[string]chan struct{} // 对应服务的 SelectInstances 阻塞到通道关闭
+	selects      map[string]int
+	unsubscribed int
+	registry     []string // 按顺序记录的注册与注销，如 "register 10.0.0.1:8000"
+}
+
+func newFakeNamingClient() *fakeNamingClient {
+	return &fakeNamingClient{
+		instances: make(map[string][]model.Instance),
+		block:     make(map[string]chan struct{}),
+		selects:   make(map[string]int),
+	}
+}
+
+func (f *fakeNamingClient) SelectInstances(param vo.SelectInstancesParam) ([]model.Instance, error) {
+	f.mu.Lock()
+	f.selects[param.ServiceName]++
+	block := f.block[param.ServiceName]
+	f.mu.Unlock()
+	if block != nil {
+		<-block
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.selectErr != nil {
+		return nil, f.selectErr
+	}
+	return f.instances[param.ServiceName], nil
+}
+
+func (f *fakeNamingClient) Subscribe(param *vo.SubscribeParam) error {
+	return nil
+}
+
+func (f *fakeNamingClient) Unsubscribe(param *vo.SubscribeParam) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.unsubscribed++
+	return nil
+}
+
+func (f *fakeNamingClient) RegisterInstance(param vo.RegisterInstanceParam) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.registry = append(f.registry, fmt.Sprintf("register %s:%d", param.Ip, param.Port))
+	return true, nil
+}
+
+func (f *fakeNamingClient) DeregisterInstance(param vo.DeregisterInstanceParam) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.registry = append(f.registry, fmt.Sprintf("deregister %s:%d", param.Ip, param.Port))
+	return true, nil
+}
+
+func (f *fakeNamingClient) selectCount(service string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.selects[service]
+}
+
+func healthy(ip string, weight float64) model.Instance {
+	return model.Instance{Ip: ip, Port: 8080, Weight: weight, Enable: true, Healthy: true}
+}
+
+func TestPickWeighted(t *testing.T) {
+	instances := []Instance{{IP: "a", Weight: 0}, {IP: "b", Weight: 3}, {IP: "c", Weight: 1}}
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		counts[pickWeighted(instances).IP]++
+	}
+	if counts["a"] != 0 {
+		t.Errorf("zero weight instance picked %d times", counts["a"])
+	}
+	// 权重 3:1，允许一定的随机误差
+	if counts["b"] < 2700 || counts["b"] > 3300 {
+		t.Errorf("counts = %v, want about 3000 for b", counts)
+	}
+
+	// 权重全为 0 时随机挑选
+	zero := []Instance{{IP: "a"}, {IP: "b"}}
+	counts = map[string]int{}
+	for i := 0; i < 200; i++ {
+		counts[pickWeighted(zero).IP]++
+	}
+	if counts["a"] == 0 || counts["b"] == 0 {
+		t.Errorf("counts = %v, want both picked when all weights are zero", counts)
+	}
+}
+
+func TestResolveRoundRobin(t *testing.T) {
+	client := newFakeNamingClient()
+	client.instances["user"] = []model.Instance{
+		healthy("10.0.0.1", 1),
+		healthy("10.0.0.2", 1),
+		{Ip: "10.0.0.3", Port: 8080, Weight: 1, Enable: true, Healthy: false},
+		healthy("10.0.0.4", 1),
+	}
+	opt := defaultOption()
+	opt.LoadBalancer = BalancerRoundRobin
+	r := newResolver(zap.NewNop(), client, opt)
+
+	var got []string
+	for i := 0; i < 6; i++ {
+		ins, err := r.Resolve("user")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, ins.IP)
+	}
+	want := []string{"10.0.0.1", "10.0.0.2", "10.0.0.4", "10.0.0.1", "10.0.0.2", "10.0.0.4"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("round robin = %v, want %v", got, want)
+		}
+	}
+	if n := client.selectCount("user"); n != 1 {
+		t.Errorf("SelectInstances called %d times, want 1", n)
+	}
+
+	if _, err := r.Resolve("empty"); !errors.Is(err, ErrNoInstance) {
+		t.Errorf("err = %v, want ErrNoInstance", err)
+	}
+}
+
+func TestResolverRetriesAfterError(t *testing.T) {
+	client := newFakeNamingClient()
+	client.selectErr = errors.New("connection refused")
+	client.instances["user"] = []model.Instance{healthy("10.0.0.1", 1)}
+	r := newResolver(zap.NewNop(), client, defaultOption())
+
+	if _, err := r.Resolve("user"); err == nil {
+		t.Fatal("expected error while nacos is unavailable")
+	}
+	client.mu.Lock()
+	client.selectErr = nil
+	client.mu.Unlock()
+
+	ins, err := r.Resolve("user")
+	if err != nil || ins.IP != "10.0.0.1" {
+		t.Fatalf("Resolve = %+v, %v", ins, err)
+	}
+	if n := client.selectCount("user"); n != 2 {
+		t.Errorf("SelectInstances called %d times, want 2", n)
+	}
+}
+
+func TestResolverDoesNotBlockOtherServices(t *testing.T) {
+	client := newFakeNamingClient()
+	client.instances["slow"] = []model.Instance{healthy("10.0.0.1", 1)}
+	client.instances["fast"] = []model.Instance{healthy("10.0.0.2", 1)}
+	release := make(chan struct{})
+	client.block["slow"] = release
+	r := newResolver(zap.NewNop(), client, defaultOption())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := r.Resolve("slow"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	// slow 的首次拉取阻塞期间，其他服务仍可解析
+	deadline := time.Now().Add(time.Second)
+	for client.selectCount("slow") == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.Resolve("fast")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("resolving fast blocked by slow")
+	}
+
+	close(release)
+	wg.Wait()
+	if n := client.selectCount("slow"); n != 1 {
+		t.Errorf("SelectInstances(slow) called %d times, want 1 for concurrent callers", n)
+	}
+
+	r.close()
+	if client.unsubscribed != 2 {
+		t.Errorf("unsubscribed = %d, want 2", client.unsubscribed)
+	}
+}
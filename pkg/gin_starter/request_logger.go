@@ -0,0 +1,84 @@
+package gin_starter
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/ahrtolia/goboot/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	HeaderRequestID   = "X-Request-ID"
+	HeaderTraceParent = "traceparent" // W3C Trace Context
+
+	requestIDKey = "goboot.request_id"
+)
+
+// RequestLogger 为每个请求创建携带 request_id、trace_id/span_id、client_ip 与 route 的 logger，
+// 放入 request context，handler、GORM、redis 通过 logger.FromContext 取得同一个 logger。
+// 请求头未携带 X-Request-ID 时生成一个，并写回响应头
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(HeaderRequestID)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = newRequestID()
+		}
+		c.Set(requestIDKey, requestID)
+		c.Header(HeaderRequestID, requestID)
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		fields := []zap.Field{
+			zap.String("request_id", requestID),
+			zap.String("client_ip", c.ClientIP()),
+			zap.String("method", c.Request.Method),
+			zap.String("route", route),
+		}
+		if traceID, spanID, ok := parseTraceParent(c.GetHeader(HeaderTraceParent)); ok {
+			fields = append(fields, zap.String("trace_id", traceID), zap.String("span_id", spanID))
+		}
+
+		ctx := logger.WithContext(c.Request.Context(), logger.L().With(fields...))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// RequestID 返回当前请求的 request ID
+func RequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// SetUserID 在鉴权完成后将 user_id 追加到请求级 logger
+func SetUserID(c *gin.Context, userID string) {
+	c.Request = c.Request.WithContext(logger.WithFields(c.Request.Context(), zap.String("user_id", userID)))
+}
+
+// parseTraceParent 解析 "00-<trace-id>-<parent-id>-<flags>"
+func parseTraceParent(header string) (traceID, spanID string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return "", "", false
+	}
+	if !isHex(parts[1]) || !isHex(parts[2]) ||
+		parts[1] == strings.Repeat("0", 32) || parts[2] == strings.Repeat("0", 16) {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
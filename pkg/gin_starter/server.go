@@ -25,6 +25,7 @@ var (
 )
 
 type Option struct {
+	Enabled      bool          `mapstructure:"enabled" default:"true"` // 存在 http 节点时默认启用
 	Port         int           `mapstructure:"port"`
 	Addr         string        `mapstructure:"addr"`
 	LogFormat    string        `mapstructure:"log_format"`
@@ -44,10 +45,19 @@ type Server struct {
 	logger     *zap.Logger
 	cleanup    func() // 旧服务器清理函数
 	started    bool
+	routes     []func(gin.IRouter) // 附加路由，配置重载重建路由时重新注册
+}
+
+func init() {
+	config.RegisterOption("http", defaultOption())
 }
 
 func NewOption(cfg *config.ConfigManager) (*Option, error) {
-	v := cfg.GetViper()
+	return LoadOption(cfg.GetViper())
+}
+
+// LoadOption 从 v 的 http 节点读取配置，未配置的项使用默认值
+func LoadOption(v *viper.Viper) (*Option, error) {
 	opt := defaultOption()
 
 	httpConfig := v.Sub("http")
@@ -65,7 +75,7 @@ func NewServer(
 	opt *Option,
 ) (*Server, error) {
 	s := &Server{
-		logger: logger,
+		logger: logger.Named("http"),
 	}
 
 	// 初始创建服务器
@@ -106,6 +116,8 @@ func (s *Server) applyConfig(opt *Option) error {
 func (s *Server) buildRouter() *gin.Engine {
 	router := gin.New()
 	router.Use(gin.Recovery())
+	// RequestLogger 在 recovery 之前，panic 的请求同样带有 request ID
+	router.Use(RequestLogger())
 	router.Use(ginzap.RecoveryWithZap(s.logger, true))
 
 	router.Use(cors.New(cors.Config{
@@ -129,15 +141,31 @@ func (s *Server) buildRouter() *gin.Engine {
 	}
 	pprof.Register(router)
 
+	s.mu.RLock()
+	routes := s.routes
+	s.mu.RUnlock()
+	for _, register := range routes {
+		register(router)
+	}
+
 	return router
 }
 
+// RegisterRoutes 注册附加路由，配置重载重建路由时会重新注册。需在 Start 之前调用
+func (s *Server) RegisterRoutes(register func(gin.IRouter)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.routes = append(s.routes, register)
+	if s.router != nil {
+		register(s.router)
+	}
+}
+
 func (s *Server) ReloadConfig(v *viper.Viper) error {
-	newOpt := defaultOption()
-	if httpConfig := v.Sub("http"); httpConfig != nil {
-		if err := httpConfig.Unmarshal(newOpt); err != nil {
-			return fmt.Errorf("failed to unmarshal http options: %w", err)
-		}
+	newOpt, err := LoadOption(v)
+	if err != nil {
+		return err
 	}
 
 	// 比较配置差异
@@ -205,6 +233,16 @@ func (s *Server) GetRouter() *gin.Engine {
 	return s.router
 }
 
+// GetOption 返回当前生效的 HTTP 配置副本
+func (s *Server) GetOption() Option {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.currentCfg == nil {
+		return *defaultOption()
+	}
+	return *s.currentCfg
+}
+
 func (s *Server) Start() error {
 	s.mu.Lock()
 	if s.started {
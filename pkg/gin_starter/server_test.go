@@ -1 +1,92 @@
 package gin_starter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahrtolia/goboot/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRequestLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger.SetGlobalLogger(zap.New(core), nil)
+	defer logger.Close()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestLogger())
+	router.GET("/users/:id", func(c *gin.Context) {
+		SetUserID(c, "u-1")
+		logger.FromContext(c.Request.Context()).Info("handled")
+		c.String(http.StatusOK, RequestID(c))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set(HeaderTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	requestID := w.Header().Get(HeaderRequestID)
+	if requestID == "" || w.Body.String() != requestID {
+		t.Fatalf("request id header = %q, body = %q", requestID, w.Body.String())
+	}
+
+	entries := logs.FilterMessage("handled").All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d records, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	want := map[string]string{
+		"request_id": requestID,
+		"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":    "00f067aa0ba902b7",
+		"route":      "/users/:id",
+		"method":     http.MethodGet,
+		"user_id":    "u-1",
+		"client_ip":  "192.0.2.1",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("%s = %v, want %v", k, fields[k], v)
+		}
+	}
+
+	// 沿用调用方传入的 request ID
+	req = httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set(HeaderRequestID, "upstream-id")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if got := w.Header().Get(HeaderRequestID); got != "upstream-id" {
+		t.Errorf("request id = %q, want upstream-id", got)
+	}
+}
+
+func TestRouterRecoversWithRequestID(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	gin.SetMode(gin.TestMode)
+	s := &Server{logger: zap.New(core)}
+	s.RegisterRoutes(func(r gin.IRouter) {
+		r.GET("/panic", func(c *gin.Context) { panic("boom") })
+	})
+	router := s.buildRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set(HeaderRequestID, "req-panic")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+	if got := w.Header().Get(HeaderRequestID); got != "req-panic" {
+		t.Errorf("request id = %q, want req-panic", got)
+	}
+	if logs.FilterLevelExact(zapcore.ErrorLevel).Len() == 0 {
+		t.Error("panic not logged by recovery")
+	}
+}
@@ -2,16 +2,18 @@ package gorm_starter
 
 import (
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/ahrtolia/goboot/pkg/config"
+	"github.com/ahrtolia/goboot/pkg/logger"
 	"github.com/google/wire"
+	"go.uber.org/zap"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
 type Option struct {
+	Enabled             bool          `mapstructure:"enabled" default:"true"`                 // 存在 db 节点时默认启用
 	DbHost              string        `mapstructure:"db_host" default:"localhost"`            // 数据库主机地址，默认 "localhost"
 	DbPort              int           `mapstructure:"db_port" default:"3306"`                 // 数据库端口，默认 3306
 	DbUser              string        `mapstructure:"db_user"`                                // 数据库用户名
@@ -30,6 +32,10 @@ type Option struct {
 	DbDriver            string        `mapstructure:"db_driver" default:"mysql"`              // 数据库驱动类型，默认 "mysql"
 }
 
+func init() {
+	config.RegisterOption("db", defaultOption())
+}
+
 func NewOption(cfg *config.ConfigManager) (*Option, error) {
 	opt := defaultOption()
 	if cfg == nil || cfg.GetViper() == nil {
@@ -48,15 +54,18 @@ func New(option *Option) *gorm.DB {
 		option.DbUser, option.DbPassword, option.DbHost, option.DbPort,
 		option.DbName, option.DbCharset, option.DbParseTime, option.DbLoc)
 
+	// 连接失败时以 fatal 级别记录并退出，zap 在退出前写出缓冲中的日志
+	l := logger.Named("gorm")
+
 	// 初始化数据库连接
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: newZapLogger(option.DbLogLevel)})
 	if err != nil {
-		log.Fatalf("无法连接到数据库: %v", err)
+		l.Fatal("无法连接到数据库", zap.Error(err))
 	}
 
 	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatalf("无法获取数据库连接池: %v", err)
+		l.Fatal("无法获取数据库连接池", zap.Error(err))
 	}
 	if option.DbMaxIdleConns > 0 {
 		sqlDB.SetMaxIdleConns(option.DbMaxIdleConns)
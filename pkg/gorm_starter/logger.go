@@ -0,0 +1,91 @@
+package gorm_starter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ahrtolia/goboot/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// slowQueryThreshold 超过该耗时的 SQL 以 warn 级别输出
+const slowQueryThreshold = 200 * time.Millisecond
+
+// zapLogger 将 GORM 日志输出到请求级 logger（logger.FromContext），名称为 gorm，
+// 可通过 logger.levels.gorm 单独调整级别；db_log_level 控制 GORM 自身输出哪些日志
+type zapLogger struct {
+	level gormlogger.LogLevel
+}
+
+func newZapLogger(level string) gormlogger.Interface {
+	return &zapLogger{level: parseGormLogLevel(level)}
+}
+
+func parseGormLogLevel(level string) gormlogger.LogLevel {
+	switch strings.ToLower(level) {
+	case "silent":
+		return gormlogger.Silent
+	case "error":
+		return gormlogger.Error
+	case "info":
+		return gormlogger.Info
+	default:
+		return gormlogger.Warn
+	}
+}
+
+func (l *zapLogger) logger(ctx context.Context) *zap.Logger {
+	// 调用位置取 GORM 计算的业务代码位置，而不是 GORM 内部
+	return logger.FromContext(ctx).Named("gorm").
+		WithOptions(zap.WithCaller(false)).
+		With(zap.String("caller", utils.FileWithLineNum()))
+}
+
+func (l *zapLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	return &zapLogger{level: level}
+}
+
+func (l *zapLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.logger(ctx).Info(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *zapLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.logger(ctx).Warn(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *zapLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.logger(ctx).Error(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *zapLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	fields := func() []zap.Field {
+		sql, rows := fc()
+		return []zap.Field{zap.String("sql", sql), zap.Int64("rows", rows), zap.Duration("elapsed", elapsed)}
+	}
+
+	switch {
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		l.logger(ctx).Error("sql error", append(fields(), zap.Error(err))...)
+	case elapsed > slowQueryThreshold && l.level >= gormlogger.Warn:
+		l.logger(ctx).Warn("slow sql", fields()...)
+	case l.level >= gormlogger.Info:
+		l.logger(ctx).Debug("sql", fields()...)
+	}
+}
@@ -0,0 +1,65 @@
+package gorm_starter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahrtolia/goboot/pkg/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestZapLoggerTrace(t *testing.T) {
+	cases := []struct {
+		name    string
+		level   gormlogger.LogLevel
+		elapsed time.Duration
+		err     error
+		want    string // 期望的消息，为空表示不输出
+		wantLvl zapcore.Level
+	}{
+		{"error", gormlogger.Warn, time.Millisecond, errors.New("duplicate key"), "sql error", zapcore.ErrorLevel},
+		{"slow", gormlogger.Warn, 300 * time.Millisecond, nil, "slow sql", zapcore.WarnLevel},
+		{"fast", gormlogger.Warn, time.Millisecond, nil, "", 0},
+		{"record not found", gormlogger.Warn, time.Millisecond, gorm.ErrRecordNotFound, "", 0},
+		{"record not found at info", gormlogger.Info, time.Millisecond, gorm.ErrRecordNotFound, "sql", zapcore.DebugLevel},
+		{"slow below error level", gormlogger.Error, 300 * time.Millisecond, nil, "", 0},
+		{"silent", gormlogger.Silent, time.Millisecond, errors.New("duplicate key"), "", 0},
+	}
+	for _, tc := range cases {
+		core, logs := observer.New(zapcore.DebugLevel)
+		ctx := logger.WithContext(context.Background(), zap.New(core))
+
+		called := false
+		fc := func() (string, int64) {
+			called = true
+			return "SELECT * FROM users WHERE id = 1", 1
+		}
+		(&zapLogger{level: tc.level}).Trace(ctx, time.Now().Add(-tc.elapsed), fc, tc.err)
+
+		entries := logs.All()
+		if tc.want == "" {
+			if len(entries) != 0 || called {
+				t.Errorf("%s: got %d records (sql evaluated %v), want none", tc.name, len(entries), called)
+			}
+			continue
+		}
+		if len(entries) != 1 {
+			t.Errorf("%s: got %d records, want 1", tc.name, len(entries))
+			continue
+		}
+		e := entries[0]
+		if e.Message != tc.want || e.Level != tc.wantLvl || e.LoggerName != "gorm" {
+			t.Errorf("%s: got %s %q from %q, want %s %q", tc.name, e.Level, e.Message, e.LoggerName, tc.wantLvl, tc.want)
+		}
+		fields := e.ContextMap()
+		if fields["sql"] != "SELECT * FROM users WHERE id = 1" || fields["rows"] != int64(1) {
+			t.Errorf("%s: fields = %v", tc.name, fields)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logger.async.overflow 的取值
+const (
+	OverflowBlock          = "block"            // 缓冲满时阻塞写入方，不丢日志
+	OverflowDropDebugFirst = "drop_debug_first" // 优先丢弃缓冲中级别最低的记录，新记录级别不高于它们时丢弃新记录
+	OverflowDropOldest     = "drop_oldest"      // 丢弃缓冲中最早的记录
+)
+
+// AsyncOption 是 logger.async 配置。启用后日志先进入有界缓冲，由后台协程写入各 sink
+type AsyncOption struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	BufferSize    int           `mapstructure:"buffer_size"`    // 缓冲的记录条数。默认: 8192
+	FlushInterval time.Duration `mapstructure:"flush_interval"` // 定期 Sync 各 sink 的间隔。默认: 1s
+	Overflow      string        `mapstructure:"overflow"`       // block, drop_debug_first, drop_oldest。默认: block
+}
+
+func defaultAsyncOption() AsyncOption {
+	return AsyncOption{
+		BufferSize:    8192,
+		FlushInterval: time.Second,
+		Overflow:      OverflowBlock,
+	}
+}
+
+var droppedRecordsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "goboot",
+	Subsystem: "logger",
+	Name:      "dropped_records_total",
+	Help:      "Number of log records dropped by the async buffer by level.",
+}, []string{"level"})
+
+func init() {
+	prometheus.MustRegister(droppedRecordsTotal)
+}
+
+const levelCount = int(zapcore.FatalLevel-zapcore.DebugLevel) + 1
+
+type asyncRecord struct {
+	seq    uint64
+	core   zapcore.Core
+	ent    zapcore.Entry
+	fields []zapcore.Field
+}
+
+// recordQueue 是单个级别的 FIFO 队列
+type recordQueue struct {
+	items []asyncRecord
+	head  int
+}
+
+func (q *recordQueue) len() int {
+	return len(q.items) - q.head
+}
+
+func (q *recordQueue) front() *asyncRecord {
+	return &q.items[q.head]
+}
+
+func (q *recordQueue) push(rec asyncRecord) {
+	if q.head > 0 && q.head == len(q.items) {
+		q.items, q.head = q.items[:0], 0
+	}
+	q.items = append(q.items, rec)
+}
+
+func (q *recordQueue) pop() asyncRecord {
+	rec := q.items[q.head]
+	q.items[q.head] = asyncRecord{}
+	q.head++
+	return rec
+}
+
+// asyncBuffer 按级别分队列保存记录，以序号恢复写入顺序，
+// 丢弃最低级别或最早的记录都只需检查各队列的队首
+type asyncBuffer struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	drained  *sync.Cond
+
+	capacity int
+	overflow string
+	queues   [levelCount]recordQueue
+	size     int
+	nextSeq  uint64
+	inflight uint64 // 正在写入 sink 的记录序号，无则为 0
+	closed   bool
+	done     chan struct{}
+}
+
+func newAsyncBuffer(opt AsyncOption) (*asyncBuffer, error) {
+	if opt.BufferSize <= 0 {
+		opt.BufferSize = 8192
+	}
+	overflow := strings.ToLower(opt.Overflow)
+	switch overflow {
+	case "":
+		overflow = OverflowBlock
+	case OverflowBlock, OverflowDropDebugFirst, OverflowDropOldest:
+	default:
+		return nil, fmt.Errorf("unknown logger.async.overflow %q, expected block, drop_debug_first or drop_oldest", opt.Overflow)
+	}
+
+	b := &asyncBuffer{capacity: opt.BufferSize, overflow: overflow, done: make(chan struct{})}
+	b.notEmpty = sync.NewCond(&b.mu)
+	b.notFull = sync.NewCond(&b.mu)
+	b.drained = sync.NewCond(&b.mu)
+	return b, nil
+}
+
+func levelIndex(l zapcore.Level) int {
+	switch {
+	case l < zapcore.DebugLevel:
+		return 0
+	case l > zapcore.FatalLevel:
+		return levelCount - 1
+	default:
+		return int(l - zapcore.DebugLevel)
+	}
+}
+
+// oldestLocked 返回队首序号最小的队列，缓冲为空时返回 -1
+func (b *asyncBuffer) oldestLocked() int {
+	idx := -1
+	for i := range b.queues {
+		if b.queues[i].len() == 0 {
+			continue
+		}
+		if idx < 0 || b.queues[i].front().seq < b.queues[idx].front().seq {
+			idx = i
+		}
+	}
+	return idx
+}
+
+// dropLocked 丢弃 idx 队列的队首记录并计数
+func (b *asyncBuffer) dropLocked(idx int) {
+	rec := b.queues[idx].pop()
+	b.size--
+	b.drained.Broadcast()
+	droppedRecordsTotal.WithLabelValues(rec.ent.Level.String()).Inc()
+}
+
+// processedLocked 判断序号不大于 seq 的记录是否均已写入或丢弃
+func (b *asyncBuffer) processedLocked(seq uint64) bool {
+	if b.inflight != 0 && b.inflight <= seq {
+		return false
+	}
+	oldest := b.oldestLocked()
+	return oldest < 0 || b.queues[oldest].front().seq > seq
+}
+
+// put 将记录放入缓冲，返回其序号；记录被丢弃时返回 0
+func (b *asyncBuffer) put(rec asyncRecord) uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	idx := levelIndex(rec.ent.Level)
+	for b.size >= b.capacity && !b.closed {
+		switch b.overflow {
+		case OverflowDropOldest:
+			b.dropLocked(b.oldestLocked())
+		case OverflowDropDebugFirst:
+			lowest := -1
+			for i := range b.queues {
+				if b.queues[i].len() > 0 {
+					lowest = i
+					break
+				}
+			}
+			if lowest >= idx {
+				droppedRecordsTotal.WithLabelValues(rec.ent.Level.String()).Inc()
+				return 0
+			}
+			b.dropLocked(lowest)
+		default:
+			b.notFull.Wait()
+		}
+	}
+	if b.closed {
+		return 0
+	}
+
+	b.nextSeq++
+	rec.seq = b.nextSeq
+	b.queues[idx].push(rec)
+	b.size++
+	b.notEmpty.Signal()
+	return rec.seq
+}
+
+// take 取出最早的记录，缓冲关闭且为空时返回 false
+func (b *asyncBuffer) take() (asyncRecord, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.size == 0 {
+		if b.closed {
+			return asyncRecord{}, false
+		}
+		b.notEmpty.Wait()
+	}
+	rec := b.queues[b.oldestLocked()].pop()
+	b.size--
+	b.inflight = rec.seq
+	b.notFull.Signal()
+	return rec, true
+}
+
+// complete 标记 take 取出的记录已写入
+func (b *asyncBuffer) complete() {
+	b.mu.Lock()
+	b.inflight = 0
+	b.drained.Broadcast()
+	b.mu.Unlock()
+}
+
+// wait 等待序号不大于 seq 的记录全部写入
+func (b *asyncBuffer) wait(seq uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for !b.processedLocked(seq) {
+		b.drained.Wait()
+	}
+}
+
+func (b *asyncBuffer) lastSeq() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nextSeq
+}
+
+// asyncCore 在调用方协程中完成级别判断与字段快照，编码与写入由后台协程完成
+type asyncCore struct {
+	zapcore.Core
+	buffer *asyncBuffer
+}
+
+// newAsyncCore 包装 core 并启动写入协程，返回的函数写完缓冲中的记录后停止协程
+func newAsyncCore(core zapcore.Core, opt AsyncOption) (zapcore.Core, func(), error) {
+	b, err := newAsyncBuffer(opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	interval := opt.FlushInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	go func() {
+		defer close(b.done)
+		for {
+			rec, ok := b.take()
+			if !ok {
+				return
+			}
+			_ = writeChecked(rec.core, rec.ent, rec.fields)
+			b.complete()
+		}
+	}()
+
+	stopTicker := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopTicker:
+				return
+			case <-ticker.C:
+				_ = core.Sync()
+			}
+		}
+	}()
+
+	stop := func() {
+		close(stopTicker)
+		b.mu.Lock()
+		b.closed = true
+		b.notEmpty.Broadcast()
+		b.notFull.Broadcast()
+		b.mu.Unlock()
+		<-b.done
+		_ = core.Sync()
+	}
+	return &asyncCore{Core: core, buffer: b}, stop, nil
+}
+
+func (c *asyncCore) With(fields []zapcore.Field) zapcore.Core {
+	return &asyncCore{Core: c.Core.With(fields), buffer: c.buffer}
+}
+
+func (c *asyncCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *asyncCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	seq := c.buffer.put(asyncRecord{core: c.Core, ent: ent, fields: snapshotFields(fields)})
+	// Panic、Fatal 之后进程可能立即退出，需等待写入完成
+	if seq > 0 && ent.Level > zapcore.ErrorLevel {
+		c.buffer.wait(seq)
+		return c.Core.Sync()
+	}
+	return nil
+}
+
+// Sync 等待此前的记录写入后刷新各 sink
+func (c *asyncCore) Sync() error {
+	c.buffer.wait(c.buffer.lastSeq())
+	return c.Core.Sync()
+}
+
+// snapshotFields 将延迟求值的字段展开为副本，避免后台编码时调用方已修改对象
+func snapshotFields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		snap, changed := snapshotField(f)
+		if !changed {
+			if out != nil {
+				out[i] = f
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, len(fields))
+			copy(out, fields[:i])
+		}
+		out[i] = snap
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+func snapshotField(f zapcore.Field) (zapcore.Field, bool) {
+	switch f.Type {
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(fmt.Stringer); ok {
+			return zap.String(f.Key, s.String()), true
+		}
+	case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType:
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		return zap.Any(f.Key, enc.Fields[f.Key]), true
+	case zapcore.InlineMarshalerType:
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		return zap.Inline(mapMarshaler(enc.Fields)), true
+	}
+	return f, false
+}
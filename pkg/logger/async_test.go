@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// gatedCore 在 gate 关闭前阻塞写入，使异步缓冲按预期填满
+type gatedCore struct {
+	zapcore.Core
+	gate chan struct{}
+}
+
+func (c *gatedCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (c *gatedCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	<-c.gate
+	return c.Core.Write(ent, fields)
+}
+
+func newGatedAsync(t *testing.T, overflow string) (*zap.Logger, *observer.ObservedLogs, chan struct{}, func()) {
+	t.Helper()
+	obs, logs := observer.New(zapcore.DebugLevel)
+	gate := make(chan struct{})
+	core, stop, err := newAsyncCore(&gatedCore{Core: obs, gate: gate}, AsyncOption{BufferSize: 2, Overflow: overflow})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := zap.New(core)
+	// 第一条被写入协程取出后阻塞，缓冲仍可容纳 2 条
+	l.Info("inflight")
+	buffer := core.(*asyncCore).buffer
+	waitFor(t, func() bool {
+		buffer.mu.Lock()
+		defer buffer.mu.Unlock()
+		return buffer.inflight != 0
+	})
+	return l, logs, gate, stop
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func messages(logs *observer.ObservedLogs) []string {
+	var out []string
+	for _, e := range logs.All() {
+		out = append(out, e.Message)
+	}
+	return out
+}
+
+func TestAsyncOverflow(t *testing.T) {
+	// 两种策略下都丢弃缓冲中最早、级别也最低的 debug
+	for _, overflow := range []string{OverflowDropOldest, OverflowDropDebugFirst} {
+		t.Run(overflow, func(t *testing.T) {
+			before := testutil.ToFloat64(droppedRecordsTotal.WithLabelValues("debug"))
+			l, logs, gate, stop := newGatedAsync(t, overflow)
+			l.Debug("debug")
+			l.Error("error")
+			l.Info("info")
+			close(gate)
+			stop()
+
+			if got := messages(logs); strings.Join(got, ",") != "inflight,error,info" {
+				t.Errorf("messages = %v", got)
+			}
+			if d := testutil.ToFloat64(droppedRecordsTotal.WithLabelValues("debug")) - before; d != 1 {
+				t.Errorf("dropped debug records = %v, want 1", d)
+			}
+		})
+	}
+
+	t.Run("drop_debug_first keeps higher levels", func(t *testing.T) {
+		l, logs, gate, stop := newGatedAsync(t, OverflowDropDebugFirst)
+		l.Error("error-1")
+		l.Error("error-2")
+		// 缓冲中没有更低级别的记录，新记录被丢弃
+		l.Warn("warn")
+		close(gate)
+		stop()
+		if got := messages(logs); len(got) != 3 || got[2] != "error-2" {
+			t.Errorf("messages = %v", got)
+		}
+	})
+}
+
+func TestAsyncBlockAndFlush(t *testing.T) {
+	l, logs, gate, stop := newGatedAsync(t, OverflowBlock)
+	l.Info("one")
+	l.Info("two")
+
+	written := make(chan struct{})
+	go func() {
+		l.Info("three")
+		close(written)
+	}()
+	select {
+	case <-written:
+		t.Fatal("write should block while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(gate)
+	<-written
+	// 关闭时写完缓冲中的全部记录
+	stop()
+	if got := messages(logs); len(got) != 4 || got[3] != "three" {
+		t.Errorf("messages = %v", got)
+	}
+}
+
+func TestAsyncSnapshotsFields(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	gate := make(chan struct{})
+	core, stop, err := newAsyncCore(&gatedCore{Core: obs, gate: gate}, AsyncOption{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags := []string{"a"}
+	zap.New(core).Info("tags", zap.Strings("tags", tags))
+	tags[0] = "changed"
+	close(gate)
+	stop()
+
+	got := logs.All()[0].ContextMap()["tags"].([]interface{})
+	if got[0] != "a" {
+		t.Errorf("tags = %v, want the value at the time of logging", got)
+	}
+}
+
+func TestCloseFlushesAsync(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	opt := defaultOption()
+	opt.Sinks = []SinkOption{{Type: SinkFile, FileName: file, BufferSize: 4096, FlushInterval: time.Hour}}
+	opt.Async = AsyncOption{Enabled: true, BufferSize: 16, FlushInterval: time.Hour}
+	core, cleanup, err := createCore(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalCore.swap(core, cleanup)
+	for i := 0; i < 100; i++ {
+		Info("record")
+	}
+	Close()
+
+	data, _ := os.ReadFile(file)
+	if n := strings.Count(string(data), `"msg":"record"`); n != 100 {
+		t.Errorf("records in file = %d, want 100", n)
+	}
+}
@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type contextKey struct{}
+
+// WithContext 返回携带 l 的 context，下游通过 FromContext 取出
+func WithContext(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext 返回 context 中的请求级 logger，不存在时返回全局 logger
+func FromContext(ctx context.Context) *zap.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(contextKey{}).(*zap.Logger); ok && l != nil {
+			return l
+		}
+	}
+	return L()
+}
+
+// WithFields 在 context 中的 logger 上追加字段，例如鉴权后追加 user_id
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	return WithContext(ctx, FromContext(ctx).With(fields...))
+}
@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	EncoderJSON    = "json"
+	EncoderConsole = "console"
+	EncoderLogfmt  = "logfmt"
+)
+
+func newEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		MessageKey:     "msg",
+		StacktraceKey:  "stacktrace",
+		EncodeLevel:    zapcore.CapitalLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
+
+func newEncoder(name string) (zapcore.Encoder, error) {
+	cfg := newEncoderConfig()
+	switch strings.ToLower(name) {
+	case EncoderJSON:
+		return zapcore.NewJSONEncoder(cfg), nil
+	case EncoderConsole:
+		return zapcore.NewConsoleEncoder(cfg), nil
+	case EncoderLogfmt:
+		return newLogfmtEncoder(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown log encoder %q, expected json, console or logfmt", name)
+	}
+}
+
+var logfmtPool = buffer.NewPool()
+
+// logfmtEncoder 输出 key=value 格式，字段按名称排序，嵌套对象与数组以 JSON 表示
+type logfmtEncoder struct {
+	*zapcore.MapObjectEncoder
+	cfg zapcore.EncoderConfig
+}
+
+func newLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{MapObjectEncoder: zapcore.NewMapObjectEncoder(), cfg: cfg}
+}
+
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := zapcore.NewMapObjectEncoder()
+	for k, v := range e.Fields {
+		clone.Fields[k] = v
+	}
+	return &logfmtEncoder{MapObjectEncoder: clone, cfg: e.cfg}
+}
+
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	enc := e.Clone().(*logfmtEncoder)
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	buf := logfmtPool.Get()
+	writePair := func(key string, value interface{}) {
+		if buf.Len() > 0 {
+			buf.AppendByte(' ')
+		}
+		buf.AppendString(logfmtKey(key))
+		buf.AppendByte('=')
+		buf.AppendString(logfmtValue(value))
+	}
+
+	if e.cfg.TimeKey != "" {
+		writePair(e.cfg.TimeKey, ent.Time.Format("2006-01-02T15:04:05.000Z0700"))
+	}
+	if e.cfg.LevelKey != "" {
+		writePair(e.cfg.LevelKey, ent.Level.String())
+	}
+	if e.cfg.NameKey != "" && ent.LoggerName != "" {
+		writePair(e.cfg.NameKey, ent.LoggerName)
+	}
+	if e.cfg.CallerKey != "" && ent.Caller.Defined {
+		writePair(e.cfg.CallerKey, ent.Caller.TrimmedPath())
+	}
+	if e.cfg.MessageKey != "" {
+		writePair(e.cfg.MessageKey, ent.Message)
+	}
+
+	keys := make([]string, 0, len(enc.Fields))
+	for k := range enc.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writePair(k, enc.Fields[k])
+	}
+
+	if e.cfg.StacktraceKey != "" && ent.Stack != "" {
+		writePair(e.cfg.StacktraceKey, ent.Stack)
+	}
+	buf.AppendByte('\n')
+	return buf, nil
+}
+
+func logfmtKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+func logfmtValue(v interface{}) string {
+	var s string
+	switch t := v.(type) {
+	case string:
+		s = t
+	case []byte:
+		s = string(t)
+	case time.Time:
+		s = t.Format(time.RFC3339Nano)
+	case time.Duration:
+		s = t.String()
+	case error:
+		s = t.Error()
+	case fmt.Stringer:
+		s = t.String()
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr, float32, float64, complex64, complex128:
+		return fmt.Sprint(t)
+	default:
+		raw, err := json.Marshal(t)
+		if err != nil {
+			s = fmt.Sprint(t)
+		} else {
+			s = string(raw)
+		}
+	}
+
+	if s == "" || strings.ContainsAny(s, " =\"\t\r\n\\") {
+		raw, _ := json.Marshal(s)
+		return string(raw)
+	}
+	return s
+}
@@ -0,0 +1,235 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RootModule 表示未命名 logger 以及未单独配置级别的模块，对应 logger.level
+const RootModule = ""
+
+var ErrInvalidLevel = errors.New("invalid log level")
+
+// levelSnapshot 是某一时刻各模块生效的日志级别，只读
+type levelSnapshot struct {
+	levels map[string]zapcore.Level // 包含 RootModule
+	min    zapcore.Level
+}
+
+// level 返回 logger 名称对应的级别，按 "." 分隔逐级匹配，gorm.sql 未配置时使用 gorm 的级别
+func (s *levelSnapshot) level(name string) zapcore.Level {
+	for name != "" {
+		if l, ok := s.levels[name]; ok {
+			return l
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return s.levels[RootModule]
+}
+
+type levelOverride struct {
+	level   zapcore.Level
+	expires time.Time // 零值表示不自动恢复
+	timer   *time.Timer
+}
+
+// levelRegistry 管理各模块的日志级别：configured 来自 logger.level / logger.levels，
+// overrides 为运行时调整（admin 接口），到期后恢复为配置的级别
+type levelRegistry struct {
+	mu         sync.Mutex
+	configured map[string]zapcore.Level
+	overrides  map[string]*levelOverride
+	current    atomic.Pointer[levelSnapshot]
+}
+
+var levels = newLevelRegistry()
+
+func newLevelRegistry() *levelRegistry {
+	r := &levelRegistry{
+		configured: map[string]zapcore.Level{RootModule: zapcore.InfoLevel},
+		overrides:  make(map[string]*levelOverride),
+	}
+	r.publishLocked()
+	return r
+}
+
+func (r *levelRegistry) snapshot() *levelSnapshot {
+	return r.current.Load()
+}
+
+func (r *levelRegistry) publishLocked() {
+	s := &levelSnapshot{levels: make(map[string]zapcore.Level, len(r.configured)+len(r.overrides))}
+	for name, l := range r.configured {
+		s.levels[name] = l
+	}
+	for name, o := range r.overrides {
+		s.levels[name] = o.level
+	}
+	s.min = zapcore.InvalidLevel
+	for _, l := range s.levels {
+		if s.min == zapcore.InvalidLevel || l < s.min {
+			s.min = l
+		}
+	}
+	r.current.Store(s)
+}
+
+// configure 应用 logger.level 与 logger.levels。不带 TTL 的运行时调整被配置覆盖，
+// 带 TTL 的临时调整保留到到期
+func (r *levelRegistry) configure(root string, modules map[string]string) error {
+	configured := map[string]zapcore.Level{RootModule: zapcore.InfoLevel}
+	if root != "" {
+		l, err := parseLevel(root)
+		if err != nil {
+			return err
+		}
+		configured[RootModule] = l
+	}
+	for name, text := range modules {
+		l, err := parseLevel(text)
+		if err != nil {
+			return fmt.Errorf("module %s: %w", name, err)
+		}
+		configured[strings.ToLower(name)] = l
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configured = configured
+	for name, o := range r.overrides {
+		if o.expires.IsZero() {
+			delete(r.overrides, name)
+		}
+	}
+	r.publishLocked()
+	return nil
+}
+
+// set 在运行时调整模块级别，ttl > 0 时到期自动恢复为配置的级别
+func (r *levelRegistry) set(module string, level zapcore.Level, ttl time.Duration) {
+	module = strings.ToLower(module)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.overrides[module]; ok && old.timer != nil {
+		old.timer.Stop()
+	}
+
+	o := &levelOverride{level: level}
+	if ttl > 0 {
+		o.expires = time.Now().Add(ttl)
+		o.timer = time.AfterFunc(ttl, func() { r.revert(module, o) })
+	}
+	r.overrides[module] = o
+	r.publishLocked()
+}
+
+func (r *levelRegistry) revert(module string, o *levelOverride) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.overrides[module] != o {
+		return
+	}
+	delete(r.overrides, module)
+	r.publishLocked()
+}
+
+// ModuleLevel 是某个模块当前生效的日志级别
+type ModuleLevel struct {
+	Module     string     `json:"module"`
+	Level      string     `json:"level"`
+	Configured string     `json:"configured,omitempty"` // 配置中的级别，未单独配置时为空
+	Expires    *time.Time `json:"expires,omitempty"`    // 临时调整的到期时间
+}
+
+func (r *levelRegistry) list() []ModuleLevel {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.snapshot()
+	out := make([]ModuleLevel, 0, len(s.levels))
+	for name, l := range s.levels {
+		m := ModuleLevel{Module: name, Level: l.String()}
+		if c, ok := r.configured[name]; ok {
+			m.Configured = c.String()
+		}
+		if o, ok := r.overrides[name]; ok && !o.expires.IsZero() {
+			expires := o.expires
+			m.Expires = &expires
+		}
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Module < out[j].Module })
+	return out
+}
+
+func parseLevel(text string) (zapcore.Level, error) {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(text)); err != nil {
+		return l, fmt.Errorf("%w: %q", ErrInvalidLevel, text)
+	}
+	return l, nil
+}
+
+// SetLevel 在运行时调整模块（RootModule 为全局）的日志级别，ttl > 0 时到期后恢复为配置的级别。
+// 不带 ttl 的调整在下一次配置重载时被 logger.levels 覆盖
+func SetLevel(module, level string, ttl time.Duration) error {
+	// 空字符串会被解析为 info，这里要求显式指定
+	if level == "" {
+		return fmt.Errorf("%w: level is required", ErrInvalidLevel)
+	}
+	l, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	levels.set(module, l, ttl)
+	return nil
+}
+
+// Levels 返回当前所有已配置或已调整模块的日志级别
+func Levels() []ModuleLevel {
+	return levels.list()
+}
+
+// Named 返回名为 name 的子 logger，其级别由 logger.levels 中同名项控制，未配置时使用 logger.level
+func Named(name string) *zap.Logger {
+	return L().Named(name)
+}
+
+// levelCore 按 logger 名称过滤日志级别，被包装的 core 本身不做级别过滤
+type levelCore struct {
+	zapcore.Core
+	registry *levelRegistry
+}
+
+func newLevelCore(core zapcore.Core, registry *levelRegistry) zapcore.Core {
+	return &levelCore{Core: core, registry: registry}
+}
+
+// Enabled 只能拿到级别，返回是否有任一模块开启了该级别，精确过滤在 Check 中完成
+func (c *levelCore) Enabled(l zapcore.Level) bool {
+	return l >= c.registry.snapshot().min
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), registry: c.registry}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < c.registry.snapshot().level(ent.LoggerName) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
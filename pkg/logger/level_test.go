@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestModuleLevels(t *testing.T) {
+	registry := newLevelRegistry()
+	if err := registry.configure("info", map[string]string{"gorm": "debug", "http": "warn"}); err != nil {
+		t.Fatal(err)
+	}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	root := zap.New(newLevelCore(core, registry))
+
+	root.Debug("root debug")
+	root.Named("gorm").Debug("gorm debug")
+	root.Named("gorm").Named("sql").Debug("gorm sql debug")
+	root.Named("http").Info("http info")
+	root.Named("redis").Info("redis info")
+
+	want := []string{"gorm debug", "gorm sql debug", "redis info"}
+	got := logs.TakeAll()
+	if len(got) != len(want) {
+		t.Fatalf("got %d records, want %v", len(got), want)
+	}
+	for i, e := range got {
+		if e.Message != want[i] {
+			t.Errorf("record[%d] = %q, want %q", i, e.Message, want[i])
+		}
+	}
+
+	// 临时调整到期后恢复为配置的级别
+	registry.set("http", zapcore.DebugLevel, 50*time.Millisecond)
+	root.Named("http").Debug("temporary debug")
+	if logs.TakeAll() == nil {
+		t.Error("override not applied")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for levelOf(registry, "http") != "warn" {
+		if time.Now().After(deadline) {
+			t.Fatalf("http level = %s, want reverted to warn", levelOf(registry, "http"))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	root.Named("http").Info("after revert")
+	if n := logs.Len(); n != 0 {
+		t.Errorf("got %d records after revert, want 0", n)
+	}
+
+	if err := registry.configure("verbose", nil); err == nil {
+		t.Error("expected error for invalid level")
+	}
+	if err := SetLevel("http", "", 0); !errors.Is(err, ErrInvalidLevel) {
+		t.Errorf("SetLevel with empty level = %v, want ErrInvalidLevel", err)
+	}
+}
+
+func TestReloadKeepsRuntimeLevels(t *testing.T) {
+	v := viper.New()
+	v.Set("logger.level", "info")
+	v.Set("logger.resource_fields", false)
+	opt := loadOptions(v)
+	if err := levels.configure(opt.Level, opt.Levels); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = levels.configure("info", nil) }()
+	defer Close()
+	r := &reloader{current: opt}
+
+	if err := SetLevel("gorm", "debug", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// 其他节点变更时保留运行时调整
+	v.Set("app.version", "1.2.3")
+	if err := r.ReloadConfig(v); err != nil {
+		t.Fatal(err)
+	}
+	if got := levelOf(levels, "gorm"); got != "debug" {
+		t.Errorf("gorm level = %s after unrelated reload, want debug", got)
+	}
+
+	// logger 节点变更时按配置重建，不带 ttl 的调整被覆盖
+	v.Set("logger.levels", map[string]interface{}{"http": "warn"})
+	if err := r.ReloadConfig(v); err != nil {
+		t.Fatal(err)
+	}
+	if got := levelOf(levels, "gorm"); got != "info" {
+		t.Errorf("gorm level = %s after logger reload, want info", got)
+	}
+}
+
+func levelOf(r *levelRegistry, module string) string {
+	for _, m := range r.list() {
+		if m.Module == module {
+			return m.Level
+		}
+	}
+	return r.snapshot().level(module).String()
+}
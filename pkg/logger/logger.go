@@ -2,170 +2,222 @@ package logger
 
 import (
 	"fmt"
+	"reflect"
+	"sync"
+
 	"github.com/ahrtolia/goboot/pkg/config"
-	"github.com/natefinch/lumberjack"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"io"
-	"os"
-	"sync"
 )
 
+// globalCore 在进程内唯一，NewLogger 与配置重载只替换其内部 core，
+// globalLogger 及其派生的子 logger 始终有效
 var (
-	globalLogger  *zap.Logger
-	globalMu      sync.RWMutex
-	globalCleanup func()
+	globalCore   = newSwapCore()
+	globalLogger = zap.New(globalCore, zap.AddCaller())
+	// 包级 Debug/Info 等函数多一层调用
+	helperLogger = globalLogger.WithOptions(zap.AddCallerSkip(1))
 )
 
 type Option struct {
-	Level          string `mapstructure:"level"`
-	Development    bool   `mapstructure:"development"`
-	FileName       string `mapstructure:"file_name"`
-	MaxSizeMB      int    `mapstructure:"max_size_mb"`
-	MaxAgeDays     int    `mapstructure:"max_age_days"`
-	Compress       bool   `mapstructure:"compress"`
-	ConsoleEnabled bool   `mapstructure:"console_enabled"`
-	FileEnabled    bool   `mapstructure:"file_enabled"`
+	Enabled        bool              `mapstructure:"enabled" default:"true"` // 存在 logger 节点时默认启用
+	Level          string            `mapstructure:"level"`
+	Levels         map[string]string `mapstructure:"levels"` // 按模块（logger 名称）设置级别，如 gorm: debug
+	Development    bool              `mapstructure:"development"`
+	FileName       string            `mapstructure:"file_name"`
+	MaxSizeMB      int               `mapstructure:"max_size_mb"`
+	MaxAgeDays     int               `mapstructure:"max_age_days"`
+	Compress       bool              `mapstructure:"compress"`
+	ConsoleEnabled bool              `mapstructure:"console_enabled"`
+	FileEnabled    bool              `mapstructure:"file_enabled"`
+	Sinks          []SinkOption      `mapstructure:"sinks"` // 配置后忽略 console_enabled、file_enabled 等
+	Redaction      RedactionOption   `mapstructure:"redaction"`
+	Async          AsyncOption       `mapstructure:"async"`
+	Sampling       SamplingOption    `mapstructure:"sampling"`
+	Dedup          DedupOption       `mapstructure:"dedup"`
+	SlogDefault    bool              `mapstructure:"slog_default"`     // 启动时将 slog.Default() 设置为写入 logger
+	RedirectStdLog bool              `mapstructure:"redirect_std_log"` // 启动时将标准库 log 的输出写入 logger
+	StdLogLevel    string            `mapstructure:"std_log_level"`    // 标准库 log 输出的级别
+	Fields         map[string]string `mapstructure:"fields"`           // 附加到每条日志的静态字段
+	ResourceFields bool              `mapstructure:"resource_fields"`  // 是否附加服务名、版本、主机名、实例 ID 等资源属性
+	Recent         RecentOption      `mapstructure:"recent"`
+
+	appName string // app.name，作为 service.name
 }
 
 func NewLogger(cfg *config.ConfigManager) (*zap.Logger, error) {
 	opt := loadOptions(cfg.GetViper())
+	if err := levels.configure(opt.Level, opt.Levels); err != nil {
+		return nil, fmt.Errorf("invalid logger level: %w", err)
+	}
+	recent.configure(opt.Recent)
+	publishTargetInfo(opt.appName)
 
-	logger, cleanup, err := createLogger(opt)
+	core, cleanup, err := createCore(opt)
 	if err != nil {
 		return nil, err
 	}
 
-	SetGlobalLogger(logger, cleanup)
-	zap.ReplaceGlobals(logger)
+	globalCore.swap(core, cleanup)
+	zap.ReplaceGlobals(globalLogger)
+	if err := installStdBridges(opt); err != nil {
+		return nil, err
+	}
+	// 重放 ConfigManager 在 logger 初始化前缓存的日志
+	cfg.AttachLogger(globalLogger)
 
 	// 注册动态配置监听
-	_ = cfg.RegisterReloader("logger", config.ConfigReloaderFunc(func(v *viper.Viper) error {
-		newOpt := loadOptions(v)
-		newLogger, newCleanup, err := createLogger(newOpt)
-		if err != nil {
-			fmt.Printf("failed to create new logger: %v\n", err)
-		}
+	_ = cfg.RegisterReloader("logger", &reloader{current: opt})
 
-		SetGlobalLogger(newLogger, newCleanup)
-		zap.ReplaceGlobals(newLogger)
+	return globalLogger, nil
+}
+
+// reloader 在 logger 节点变更时重建 core
+type reloader struct {
+	mu      sync.Mutex
+	current *Option
+}
+
+func (r *reloader) ReloadConfig(v *viper.Viper) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 其他配置变更时 logger 节点不变，不重建 core，也保留运行时调整的模块级别
+	newOpt := loadOptions(v)
+	if reflect.DeepEqual(r.current, newOpt) {
 		return nil
-	}))
+	}
+	if err := levels.configure(newOpt.Level, newOpt.Levels); err != nil {
+		return fmt.Errorf("invalid logger level: %w", err)
+	}
+	recent.configure(newOpt.Recent)
+	publishTargetInfo(newOpt.appName)
+	newCore, newCleanup, err := createCore(newOpt)
+	if err != nil {
+		return fmt.Errorf("failed to create new logger: %w", err)
+	}
 
-	return logger, nil
+	// 已注入各模块的 logger 不变，旧的 sink 在写入完成后关闭
+	globalCore.swap(newCore, newCleanup)
+	r.current = newOpt
+	return nil
 }
 
-func loadOptions(v *viper.Viper) *Option {
-	opt := &Option{
-		Level:       "info",
-		Development: false,
+func init() {
+	config.RegisterOption("logger", defaultOption())
+}
+
+func defaultOption() *Option {
+	return &Option{
+		Level:          "info",
+		Development:    false,
+		Redaction:      defaultRedactionOption(),
+		Async:          defaultAsyncOption(),
+		Sampling:       defaultSamplingOption(),
+		Dedup:          defaultDedupOption(),
+		StdLogLevel:    "info",
+		ResourceFields: true,
+		Recent:         defaultRecentOption(),
 	}
+}
+
+func loadOptions(v *viper.Viper) *Option {
+	opt := defaultOption()
 	_ = v.UnmarshalKey("logger", opt)
+	opt.appName = v.GetString("app.name")
 	return opt
 }
 
-func createLogger(opt *Option) (*zap.Logger, func(), error) {
-	atomicLevel := zap.NewAtomicLevel()
-	_ = atomicLevel.UnmarshalText([]byte(opt.Level))
-
-	encoderConfig := zapcore.EncoderConfig{
-		TimeKey:        "time",
-		LevelKey:       "level",
-		NameKey:        "logger",
-		CallerKey:      "caller",
-		MessageKey:     "msg",
-		StacktraceKey:  "stacktrace",
-		EncodeLevel:    zapcore.CapitalLevelEncoder,
-		EncodeTime:     zapcore.ISO8601TimeEncoder,
-		EncodeDuration: zapcore.StringDurationEncoder,
-		EncodeCaller:   zapcore.ShortCallerEncoder,
-	}
-
-	cores := make([]zapcore.Core, 0)
-
-	if opt.ConsoleEnabled {
-		consoleCore := zapcore.NewCore(
-			zapcore.NewConsoleEncoder(encoderConfig),
-			zapcore.Lock(os.Stdout),
-			atomicLevel,
-		)
-		cores = append(cores, consoleCore)
-	}
-
-	var fileSyncer zapcore.WriteSyncer
-	if opt.FileEnabled {
-		lj := &lumberjack.Logger{
-			Filename: opt.FileName,
-			MaxSize:  opt.MaxSizeMB,
-			MaxAge:   opt.MaxAgeDays,
-			Compress: opt.Compress,
+// createCore 按配置创建 core 及释放其资源的 cleanup
+func createCore(opt *Option) (zapcore.Core, func(), error) {
+	var rules *redactor
+	if opt.Redaction.Enabled {
+		r, err := newRedactor(opt.Redaction)
+		if err != nil {
+			return nil, nil, err
 		}
-		fileSyncer = zapcore.AddSync(lj)
-		fileCore := zapcore.NewCore(
-			zapcore.NewJSONEncoder(encoderConfig),
-			fileSyncer,
-			atomicLevel,
-		)
-		cores = append(cores, fileCore)
+		rules = r
+	}
+
+	cores, closers, err := buildSinks(opt.sinkOptions())
+	if err != nil {
+		return nil, nil, err
 	}
 
+	if opt.Recent.Enabled {
+		cores = append(cores, newRecentCore(recent))
+	}
+
+	// 各输出仅按自身 level 过滤，模块级别由 levelCore 统一处理
 	core := zapcore.NewTee(cores...)
-	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+	stopAsync := func() {}
+	if opt.Async.Enabled {
+		asyncCore, stop, err := newAsyncCore(core, opt.Async)
+		if err != nil {
+			for _, c := range closers {
+				c()
+			}
+			return nil, nil, err
+		}
+		core, stopAsync = asyncCore, stop
+	}
+	if rules != nil {
+		core = newRedactCore(core, rules)
+	}
+	if opt.Sampling.Enabled {
+		core = newSamplingCore(core, opt.Sampling)
+	}
+	// 去重在采样之前，汇总中的重复次数不受采样影响
+	stopDedup := func() {}
+	if opt.Dedup.Enabled {
+		core, stopDedup = newDedupCore(core, opt.Dedup)
+	}
+	if fields := staticFields(opt); len(fields) > 0 {
+		core = core.With(fields)
+	}
+	core = newLevelCore(core, levels)
 
 	cleanup := func() {
-		_ = logger.Sync()
-		if fileSyncer != nil {
-			if closer, ok := fileSyncer.(io.Closer); ok {
-				_ = closer.Close()
-			}
+		// 先输出去重汇总、写完异步缓冲中的记录，再关闭各输出
+		stopDedup()
+		stopAsync()
+		_ = core.Sync()
+		for _, c := range closers {
+			c()
 		}
 	}
 
-	return logger, cleanup, nil
+	return core, cleanup, nil
 }
 
+// L 返回全局 logger，配置重载后依然有效
 func L() *zap.Logger {
-	globalMu.RLock()
-	defer globalMu.RUnlock()
 	return globalLogger
 }
 
+// SetGlobalLogger 将全局 logger 的输出切换到 l，原有的输出在写入完成后执行其 cleanup
 func SetGlobalLogger(l *zap.Logger, cleanup func()) {
-	globalMu.Lock()
-	defer globalMu.Unlock()
-
-	// 先关闭旧的
-	if globalCleanup != nil {
-		globalCleanup()
-	}
-
-	globalLogger = l
-	globalCleanup = cleanup
+	globalCore.swap(l.Core(), cleanup)
 }
 
+// Close 写完异步缓冲中的记录，刷新并关闭当前输出，之后的日志被丢弃
 func Close() {
-	globalMu.Lock()
-	defer globalMu.Unlock()
-	if globalCleanup != nil {
-		globalCleanup()
-	}
-	globalLogger = nil
-	globalCleanup = nil
+	globalCore.swap(zapcore.NewNopCore(), nil)
 }
 
 func Debug(msg string, fields ...zap.Field) {
-	L().Debug(msg, fields...)
+	helperLogger.Debug(msg, fields...)
 }
 
 func Info(msg string, fields ...zap.Field) {
-	L().Info(msg, fields...)
+	helperLogger.Info(msg, fields...)
 }
 
 func Warn(msg string, fields ...zap.Field) {
-	L().Warn(msg, fields...)
+	helperLogger.Warn(msg, fields...)
 }
 
 func Error(msg string, fields ...zap.Field) {
-	L().Error(msg, fields...)
+	helperLogger.Error(msg, fields...)
 }
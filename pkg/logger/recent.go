@@ -0,0 +1,261 @@
+package logger
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrRecentDisabled 表示未启用 logger.recent
+var ErrRecentDisabled = errors.New("recent log buffer is not enabled")
+
+// RecentOption 是 logger.recent 配置：在内存中按级别保留最近的日志，供管理接口查询与实时查看
+type RecentOption struct {
+	Enabled  bool `mapstructure:"enabled"`
+	PerLevel int  `mapstructure:"per_level"` // 每个级别保留的条数。默认: 1000
+}
+
+func defaultRecentOption() RecentOption {
+	return RecentOption{PerLevel: 1000}
+}
+
+// Record 是内存中保留的一条日志，字段已经过脱敏
+type Record struct {
+	Time      time.Time              `json:"time"`
+	Level     string                 `json:"level"`
+	Module    string                 `json:"module,omitempty"`
+	Message   string                 `json:"message"`
+	Caller    string                 `json:"caller,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+
+	seq   uint64
+	level zapcore.Level
+}
+
+// RecentFilter 是查询与实时查看的过滤条件，零值字段不参与过滤
+type RecentFilter struct {
+	Level     zapcore.Level // 最低级别
+	Module    string        // 模块及其子模块，如 redis 匹配 redis 与 redis.cluster
+	RequestID string
+	Since     time.Time
+	Until     time.Time
+	Limit     int // 查询时只返回最近的 Limit 条
+}
+
+// Match 判断记录是否满足过滤条件
+func (f RecentFilter) Match(r Record) bool {
+	if r.level < f.Level {
+		return false
+	}
+	if f.Module != "" && r.Module != f.Module && !strings.HasPrefix(r.Module, f.Module+".") {
+		return false
+	}
+	if f.RequestID != "" && r.RequestID != f.RequestID {
+		return false
+	}
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && r.Time.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// recentRing 是单个级别的环形缓冲
+type recentRing struct {
+	records []Record
+	next    int
+	full    bool
+}
+
+func (r *recentRing) add(rec Record) {
+	r.records[r.next] = rec
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *recentRing) each(fn func(Record)) {
+	if r.full {
+		for _, rec := range r.records[r.next:] {
+			fn(rec)
+		}
+	}
+	for _, rec := range r.records[:r.next] {
+		fn(rec)
+	}
+}
+
+// recentStore 在配置重载之间保留，重载时按新的 per_level 调整容量
+type recentStore struct {
+	mu          sync.RWMutex
+	enabled     bool
+	rings       [levelCount]*recentRing
+	seq         uint64
+	subscribers map[chan Record]RecentFilter
+}
+
+var recent = &recentStore{subscribers: make(map[chan Record]RecentFilter)}
+
+func (s *recentStore) configure(opt RecentOption) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.enabled = opt.Enabled
+	if !opt.Enabled {
+		s.rings = [levelCount]*recentRing{}
+		// 关闭订阅通道，实时查看随之结束
+		for ch := range s.subscribers {
+			close(ch)
+			delete(s.subscribers, ch)
+		}
+		return
+	}
+	size := opt.PerLevel
+	if size <= 0 {
+		size = 1000
+	}
+	for i, old := range s.rings {
+		ring := &recentRing{records: make([]Record, size)}
+		if old != nil {
+			// 保留最近的记录
+			old.each(ring.add)
+		}
+		s.rings[i] = ring
+	}
+}
+
+func (s *recentStore) add(rec Record) {
+	s.mu.Lock()
+	if !s.enabled {
+		s.mu.Unlock()
+		return
+	}
+	s.seq++
+	rec.seq = s.seq
+	s.rings[levelIndex(rec.level)].add(rec)
+	for ch, f := range s.subscribers {
+		if !f.Match(rec) {
+			continue
+		}
+		// 不阻塞写日志，订阅方消费过慢时丢弃
+		select {
+		case ch <- rec:
+		default:
+		}
+	}
+	s.mu.Unlock()
+}
+
+// RecentLogs 按时间顺序返回内存中满足条件的日志
+func RecentLogs(f RecentFilter) ([]Record, error) {
+	recent.mu.RLock()
+	if !recent.enabled {
+		recent.mu.RUnlock()
+		return nil, ErrRecentDisabled
+	}
+	var out []Record
+	for _, ring := range recent.rings {
+		ring.each(func(rec Record) {
+			if f.Match(rec) {
+				out = append(out, rec)
+			}
+		})
+	}
+	recent.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].seq < out[j].seq })
+	if f.Limit > 0 && len(out) > f.Limit {
+		out = out[len(out)-f.Limit:]
+	}
+	return out, nil
+}
+
+// TailLogs 订阅之后写入的满足条件的日志，调用返回的函数取消订阅。
+// logger.recent 被关闭时通道随之关闭
+func TailLogs(f RecentFilter) (<-chan Record, func(), error) {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+	if !recent.enabled {
+		return nil, nil, ErrRecentDisabled
+	}
+	ch := make(chan Record, 256)
+	recent.subscribers[ch] = f
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			recent.mu.Lock()
+			delete(recent.subscribers, ch)
+			recent.mu.Unlock()
+		})
+	}
+	return ch, cancel, nil
+}
+
+// recentCore 作为一个 sink 将日志写入 recent，位于脱敏之后
+type recentCore struct {
+	context *zapcore.MapObjectEncoder
+	store   *recentStore
+}
+
+func newRecentCore(store *recentStore) zapcore.Core {
+	return &recentCore{context: zapcore.NewMapObjectEncoder(), store: store}
+}
+
+func (c *recentCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c *recentCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := zapcore.NewMapObjectEncoder()
+	for k, v := range c.context.Fields {
+		enc.Fields[k] = v
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &recentCore{context: enc, store: c.store}
+}
+
+func (c *recentCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (c *recentCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for k, v := range c.context.Fields {
+		enc.Fields[k] = v
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	rec := Record{
+		Time:    ent.Time,
+		Level:   ent.Level.String(),
+		Module:  ent.LoggerName,
+		Message: ent.Message,
+		Fields:  enc.Fields,
+		level:   ent.Level,
+	}
+	if ent.Caller.Defined {
+		rec.Caller = ent.Caller.TrimmedPath()
+	}
+	if id, ok := enc.Fields["request_id"].(string); ok {
+		rec.RequestID = id
+	}
+	c.store.add(rec)
+	return nil
+}
+
+func (c *recentCore) Sync() error {
+	return nil
+}
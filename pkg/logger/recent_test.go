@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRecentLogs(t *testing.T) {
+	opt := defaultOption()
+	opt.ResourceFields = false
+	opt.Recent = RecentOption{Enabled: true, PerLevel: 2}
+	recent.configure(opt.Recent)
+	defer recent.configure(RecentOption{})
+
+	core, cleanup, err := createCore(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalCore.swap(core, cleanup)
+	defer Close()
+
+	tail, cancel, err := TailLogs(RecentFilter{Level: zapcore.ErrorLevel, Module: "redis"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+
+	start := time.Now()
+	req := Named("http").With(zap.String("request_id", "req-1"))
+	for i := 0; i < 3; i++ {
+		req.Info("handled", zap.Int("n", i))
+	}
+	Named("redis.cluster").Error("connection refused", zap.String("password", "secret"))
+	Named("redisx").Error("not a redis submodule")
+
+	// 每个级别只保留最近 2 条
+	all, err := RecentLogs(RecentFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 || all[0].Fields["n"] != int64(1) || all[2].Module != "redis.cluster" {
+		t.Fatalf("records = %+v", all)
+	}
+	if all[0].RequestID != "req-1" || all[2].Fields["password"] != defaultRedactionOption().Mask {
+		t.Errorf("records = %+v, want request id and redacted fields", all)
+	}
+
+	cases := []struct {
+		name   string
+		filter RecentFilter
+		want   int
+	}{
+		{"level", RecentFilter{Level: zapcore.ErrorLevel}, 2},
+		{"module prefix", RecentFilter{Module: "redis"}, 1},
+		{"request id", RecentFilter{RequestID: "req-1"}, 2},
+		{"since", RecentFilter{Since: start.Add(time.Hour)}, 0},
+		{"until", RecentFilter{Until: start.Add(-time.Hour)}, 0},
+		{"limit", RecentFilter{Limit: 1}, 1},
+	}
+	for _, tc := range cases {
+		got, _ := RecentLogs(tc.filter)
+		if len(got) != tc.want {
+			t.Errorf("%s: records = %d, want %d", tc.name, len(got), tc.want)
+		}
+	}
+
+	select {
+	case rec := <-tail:
+		if rec.Message != "connection refused" {
+			t.Errorf("tail = %+v", rec)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no record on tail")
+	}
+	select {
+	case rec := <-tail:
+		t.Errorf("unexpected record on tail: %+v", rec)
+	default:
+	}
+
+	recent.configure(RecentOption{})
+	if _, ok := <-tail; ok {
+		t.Error("tail channel not closed after recent is disabled")
+	}
+	cancel() // 通道关闭后取消订阅仍然安全
+	if _, err := RecentLogs(RecentFilter{}); !errors.Is(err, ErrRecentDisabled) {
+		t.Errorf("err = %v, want ErrRecentDisabled", err)
+	}
+}
@@ -0,0 +1,253 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RedactionOption 是 logger.redaction 节点，对字段名与消息、字段值中的敏感内容脱敏
+type RedactionOption struct {
+	Enabled  bool     `mapstructure:"enabled" default:"true"`
+	Fields   []string `mapstructure:"fields"`   // 字段名包含其中任一项（不区分大小写）时整体脱敏，含嵌套对象中的字段
+	Patterns []string `mapstructure:"patterns"` // 正则表达式，消息与字符串值中的匹配部分被替换；默认的银行卡号规则需通过 Luhn 校验
+	Mask     string   `mapstructure:"mask" default:"******"`
+}
+
+// cardNumberPattern 是默认的银行卡号规则，匹配内容通过 Luhn 校验才脱敏，避免误伤时间戳、订单号等长数字
+const cardNumberPattern = `\b(?:\d[ -]?){12,18}\d\b`
+
+func defaultRedactionOption() RedactionOption {
+	return RedactionOption{
+		Enabled: true,
+		Fields:  []string{"password", "passwd", "secret", "token", "authorization", "id_card", "phone"},
+		Patterns: []string{
+			`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`, // 邮箱
+			cardNumberPattern, // 银行卡号
+		},
+		Mask: "******",
+	}
+}
+
+type redactor struct {
+	fields   []string
+	patterns []redactPattern
+	mask     string
+}
+
+type redactPattern struct {
+	re   *regexp.Regexp
+	luhn bool // 匹配内容需通过 Luhn 校验
+}
+
+func newRedactor(opt RedactionOption) (*redactor, error) {
+	r := &redactor{mask: opt.Mask}
+	for _, f := range opt.Fields {
+		if f = strings.ToLower(strings.TrimSpace(f)); f != "" {
+			r.fields = append(r.fields, f)
+		}
+	}
+	for _, p := range opt.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, redactPattern{re: re, luhn: p == cardNumberPattern})
+	}
+	return r, nil
+}
+
+func (r *redactor) sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, f := range r.fields {
+		if strings.Contains(key, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *redactor) redactString(s string) string {
+	for _, p := range r.patterns {
+		if !p.luhn {
+			s = p.re.ReplaceAllString(s, r.mask)
+			continue
+		}
+		s = p.re.ReplaceAllStringFunc(s, func(m string) string {
+			if luhnValid(m) {
+				return r.mask
+			}
+			return m
+		})
+	}
+	return s
+}
+
+// luhnValid 对数字做 Luhn 校验，忽略空格与 -
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
+
+// redactValue 处理由 MapObjectEncoder 展开、再经 JSON 归一化后的值，数字为 json.Number
+func (r *redactor) redactValue(key string, v interface{}) interface{} {
+	if key != "" && r.sensitiveKey(key) {
+		return r.mask
+	}
+	switch t := v.(type) {
+	case string:
+		return r.redactString(t)
+	case json.Number:
+		// 整数还原为 int64，超出 int64 范围的整数保持 json.Number 按原样输出
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		if !strings.ContainsAny(string(t), ".eE") {
+			return t
+		}
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+		return t
+	case map[string]interface{}:
+		for k, val := range t {
+			t[k] = r.redactValue(k, val)
+		}
+		return t
+	case []interface{}:
+		for i, val := range t {
+			t[i] = r.redactValue("", val)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func (r *redactor) redactField(f zapcore.Field) zapcore.Field {
+	if f.Type == zapcore.NamespaceType || f.Type == zapcore.SkipType {
+		return f
+	}
+	if r.sensitiveKey(f.Key) {
+		return zap.String(f.Key, r.mask)
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		if s := r.redactString(f.String); s != f.String {
+			return zap.String(f.Key, s)
+		}
+		return f
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			if s := r.redactString(err.Error()); s != err.Error() {
+				return zap.String(f.Key, s)
+			}
+		}
+		return f
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(fmt.Stringer); ok {
+			return zap.String(f.Key, r.redactString(s.String()))
+		}
+		return f
+	case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType, zapcore.InlineMarshalerType, zapcore.ReflectType:
+		return r.redactComplex(f)
+	default:
+		return f
+	}
+}
+
+// redactComplex 将对象、数组与 zap.Any 的任意值展开为 map/slice 后脱敏
+func (r *redactor) redactComplex(f zapcore.Field) zapcore.Field {
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+
+	var value interface{} = enc.Fields
+	if f.Type != zapcore.InlineMarshalerType {
+		value = enc.Fields[f.Key]
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return f
+	}
+	// UseNumber 避免 int64 经 float64 丢失精度
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var normalized interface{}
+	if err = dec.Decode(&normalized); err != nil {
+		return f
+	}
+
+	redacted := r.redactValue("", normalized)
+	if f.Type == zapcore.InlineMarshalerType {
+		return zap.Inline(mapMarshaler(redacted.(map[string]interface{})))
+	}
+	return zap.Any(f.Key, redacted)
+}
+
+func (r *redactor) redactFields(fields []zapcore.Field) []zapcore.Field {
+	out := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		out[i] = r.redactField(f)
+	}
+	return out
+}
+
+type mapMarshaler map[string]interface{}
+
+func (m mapMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for k, v := range m {
+		if err := enc.AddReflected(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// redactCore 在写入各 sink 之前对消息与字段脱敏
+type redactCore struct {
+	zapcore.Core
+	redactor *redactor
+}
+
+func newRedactCore(core zapcore.Core, r *redactor) zapcore.Core {
+	return &redactCore{Core: core, redactor: r}
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.redactor.redactFields(fields)), redactor: c.redactor}
+}
+
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = c.redactor.redactString(ent.Message)
+	return writeChecked(c.Core, ent, c.redactor.redactFields(fields))
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type loginRequest struct {
+	ID       int64  `json:"id"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+	Profile  struct {
+		Email string `json:"email"`
+		Phone string `json:"phone"`
+	} `json:"profile"`
+}
+
+func TestRedaction(t *testing.T) {
+	r, err := newRedactor(defaultRedactionOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := zap.New(newRedactCore(core, r)).With(zap.String("Authorization", "Bearer abc"))
+
+	req := loginRequest{ID: 1<<62 + 1, User: "alice", Password: "p@ss"}
+	req.Profile.Email = "alice@example.com"
+	req.Profile.Phone = "13800000000"
+
+	l.Info("login from alice@example.com",
+		zap.Any("req", req),
+		zap.String("card", "card 6222 0212 3456 7890 128"),
+		zap.String("order", "order 1729296000000 created"), // 不通过 Luhn 校验的长数字不脱敏
+		zap.Error(errors.New("user bob@example.com not found")),
+		zap.String("access_token", "t-1"),
+	)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d records, want 1", len(entries))
+	}
+	got := entries[0]
+	if got.Message != "login from ******" {
+		t.Errorf("message = %q", got.Message)
+	}
+
+	fields := got.ContextMap()
+	want := map[string]interface{}{
+		"Authorization": "******",
+		"access_token":  "******",
+		"card":          "card ******",
+		"order":         "order 1729296000000 created",
+		"error":         "user ****** not found",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("%s = %v, want %v", k, fields[k], v)
+		}
+	}
+
+	reqFields, _ := fields["req"].(map[string]interface{})
+	profile, _ := reqFields["profile"].(map[string]interface{})
+	if reqFields["id"] != int64(1<<62+1) {
+		t.Errorf("req.id = %v (%T), want int64 without precision loss", reqFields["id"], reqFields["id"])
+	}
+	if reqFields["user"] != "alice" || reqFields["password"] != "******" ||
+		profile["email"] != "******" || profile["phone"] != "******" {
+		t.Errorf("req = %v", fields["req"])
+	}
+}
+
+func TestLuhnValid(t *testing.T) {
+	cases := map[string]bool{
+		"4111 1111 1111 1111":     true,
+		"6222-0212-3456-7890-128": true,
+		"6222 0212 3456 7890 123": false,
+		"1729296000000":           false,
+		"":                        false,
+	}
+	for in, want := range cases {
+		if got := luhnValid(in); got != want {
+			t.Errorf("luhnValid(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"os"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// 构建时可通过 -ldflags "-X github.com/ahrtolia/goboot/pkg/logger.Version=v1.2.3" 指定，
+// 为空时从构建信息中读取
+var (
+	Version string
+	Commit  string
+)
+
+// 资源属性的 key，与 OpenTelemetry 资源语义约定一致。指标通过 target_info 暴露相同的属性
+const (
+	AttrServiceName  = "service.name"
+	AttrServiceVer   = "service.version"
+	AttrVCSRevision  = "vcs.revision"
+	AttrHostName     = "host.name"
+	AttrPodName      = "k8s.pod.name"
+	AttrPodNamespace = "k8s.namespace.name"
+	AttrInstanceID   = "service.instance.id"
+)
+
+const (
+	envPodName        = "POD_NAME"
+	envPodNamespace   = "POD_NAMESPACE"
+	envInstanceID     = "INSTANCE_ID"
+	develBuildVersion = "(devel)"
+)
+
+// Resource 描述当前进程的身份，附加到每条日志上
+type Resource struct {
+	ServiceName  string
+	Version      string
+	Commit       string
+	Hostname     string
+	PodName      string
+	PodNamespace string
+	InstanceID   string // 环境变量 INSTANCE_ID，未设置时为进程启动时生成的 UUID
+}
+
+var processResource = sync.OnceValue(func() Resource {
+	r := Resource{
+		Version:      Version,
+		Commit:       Commit,
+		PodName:      os.Getenv(envPodName),
+		PodNamespace: os.Getenv(envPodNamespace),
+		InstanceID:   os.Getenv(envInstanceID),
+	}
+	r.Hostname, _ = os.Hostname()
+	if r.InstanceID == "" {
+		r.InstanceID = uuid.NewString()
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if r.Version == "" && info.Main.Version != develBuildVersion {
+			r.Version = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" && r.Commit == "" {
+				r.Commit = s.Value
+			}
+		}
+	}
+	return r
+})
+
+// DetectResource 返回当前进程的资源属性，serviceName 通常为 app.name
+func DetectResource(serviceName string) Resource {
+	r := processResource()
+	r.ServiceName = serviceName
+	return r
+}
+
+// Attributes 返回非空的资源属性
+func (r Resource) Attributes() map[string]string {
+	attrs := make(map[string]string, 7)
+	for k, v := range map[string]string{
+		AttrServiceName:  r.ServiceName,
+		AttrServiceVer:   r.Version,
+		AttrVCSRevision:  r.Commit,
+		AttrHostName:     r.Hostname,
+		AttrPodName:      r.PodName,
+		AttrPodNamespace: r.PodNamespace,
+		AttrInstanceID:   r.InstanceID,
+	} {
+		if v != "" {
+			attrs[k] = v
+		}
+	}
+	return attrs
+}
+
+// resourceAttrs 是 target_info 的标签顺序，标签名为属性 key 中的 . 替换为 _
+var resourceAttrs = []string{
+	AttrServiceName, AttrServiceVer, AttrVCSRevision, AttrHostName,
+	AttrPodName, AttrPodNamespace, AttrInstanceID,
+}
+
+// targetInfo 按 OpenTelemetry 的 Prometheus 兼容约定以 target_info 暴露资源属性，
+// 指标可按 service.instance.id 等与日志关联
+var targetInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "target_info",
+	Help: "Resource attributes of this process, the same as attached to log records.",
+}, func() []string {
+	labels := make([]string, len(resourceAttrs))
+	for i, attr := range resourceAttrs {
+		labels[i] = strings.ReplaceAll(attr, ".", "_")
+	}
+	return labels
+}())
+
+func init() {
+	prometheus.MustRegister(targetInfo)
+}
+
+// publishTargetInfo 以 serviceName 对应的资源属性更新 target_info，不受 resource_fields 影响
+func publishTargetInfo(serviceName string) {
+	attrs := DetectResource(serviceName).Attributes()
+	values := make([]string, len(resourceAttrs))
+	for i, attr := range resourceAttrs {
+		values[i] = attrs[attr]
+	}
+	targetInfo.Reset()
+	targetInfo.WithLabelValues(values...).Set(1)
+}
+
+// staticFields 合并资源属性与 logger.fields，同名时以 logger.fields 为准，按 key 排序
+func staticFields(opt *Option) []zap.Field {
+	attrs := make(map[string]interface{})
+	if opt.ResourceFields {
+		for k, v := range DetectResource(opt.appName).Attributes() {
+			attrs[k] = v
+		}
+	}
+	for k, v := range opt.Fields {
+		attrs[k] = v
+	}
+
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]zap.Field, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, zap.Any(k, attrs[k]))
+	}
+	return fields
+}
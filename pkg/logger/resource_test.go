@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestStaticFields(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	opt := defaultOption()
+	opt.appName = "order-service"
+	opt.Fields = map[string]string{"env": "prod", AttrHostName: "overridden"}
+	opt.Sinks = []SinkOption{{Type: SinkFile, FileName: file}}
+
+	core, cleanup, err := createCore(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalCore.swap(core, cleanup)
+	Named("redis").Info("connected")
+	Close()
+
+	data, _ := os.ReadFile(file)
+	var record map[string]interface{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("decode %q: %v", data, err)
+	}
+	if record[AttrServiceName] != "order-service" || record["env"] != "prod" || record[AttrHostName] != "overridden" {
+		t.Errorf("record = %v", record)
+	}
+	if id, _ := record[AttrInstanceID].(string); id == "" || id != DetectResource("").InstanceID {
+		t.Errorf("instance id = %v, want the process instance id", record[AttrInstanceID])
+	}
+
+	opt.ResourceFields = false
+	opt.Fields = nil
+	if fields := staticFields(opt); len(fields) != 0 {
+		t.Errorf("fields = %v, want none when resource_fields is disabled", fields)
+	}
+}
+
+func TestTargetInfo(t *testing.T) {
+	publishTargetInfo("order-service")
+	publishTargetInfo("payment-service") // 重载后只保留当前的属性
+
+	if n := testutil.CollectAndCount(targetInfo); n != 1 {
+		t.Fatalf("target_info series = %d, want 1", n)
+	}
+	r := DetectResource("payment-service")
+	got := testutil.ToFloat64(targetInfo.WithLabelValues(r.ServiceName, r.Version, r.Commit, r.Hostname, r.PodName, r.PodNamespace, r.InstanceID))
+	if got != 1 {
+		t.Errorf("target_info = %v, want 1 with the log resource attributes", got)
+	}
+}
@@ -0,0 +1,224 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SamplingOption 是 logger.sampling 配置：每个 tick 内相同级别与消息的日志，
+// 前 initial 条全部输出，之后每 thereafter 条输出一条
+type SamplingOption struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Tick       time.Duration `mapstructure:"tick"`       // 默认: 1s
+	Initial    int           `mapstructure:"initial"`    // 默认: 100
+	Thereafter int           `mapstructure:"thereafter"` // 默认: 100
+}
+
+// DedupOption 是 logger.dedup 配置：窗口内来自同一位置的相同消息只输出第一条，
+// 窗口结束时输出一条 "repeated N times" 汇总
+type DedupOption struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Window  time.Duration `mapstructure:"window"`   // 默认: 1s
+	MaxKeys int           `mapstructure:"max_keys"` // 同时跟踪的消息数上限，超出后不再去重。默认: 10000
+}
+
+func defaultSamplingOption() SamplingOption {
+	return SamplingOption{Tick: time.Second, Initial: 100, Thereafter: 100}
+}
+
+func defaultDedupOption() DedupOption {
+	return DedupOption{Window: time.Second, MaxKeys: 10000}
+}
+
+var sampledRecordsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "goboot",
+	Subsystem: "logger",
+	Name:      "sampled_records_total",
+	Help:      "Number of log records dropped by sampling by level.",
+}, []string{"level"})
+
+func init() {
+	prometheus.MustRegister(sampledRecordsTotal)
+}
+
+// samplingCore 在 Write 时才交给 zap 的 sampler 计数。
+// swapCore 会先 Check 一次再在 Write 中重新 Check，若在 Check 中计数会重复
+type samplingCore struct {
+	zapcore.Core
+	sampler zapcore.Core
+}
+
+func newSamplingCore(core zapcore.Core, opt SamplingOption) zapcore.Core {
+	if opt.Tick <= 0 {
+		opt.Tick = time.Second
+	}
+	if opt.Initial <= 0 {
+		opt.Initial = 100
+	}
+	if opt.Thereafter <= 0 {
+		opt.Thereafter = 100
+	}
+	sampler := zapcore.NewSamplerWithOptions(core, opt.Tick, opt.Initial, opt.Thereafter,
+		zapcore.SamplerHook(func(ent zapcore.Entry, dec zapcore.SamplingDecision) {
+			if dec&zapcore.LogDropped != 0 {
+				sampledRecordsTotal.WithLabelValues(ent.Level.String()).Inc()
+			}
+		}))
+	return &samplingCore{Core: core, sampler: sampler}
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplingCore{Core: c.Core.With(fields), sampler: c.sampler.With(fields)}
+}
+
+func (c *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *samplingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return writeChecked(c.sampler, ent, fields)
+}
+
+type dedupKey struct {
+	level   zapcore.Level
+	name    string
+	caller  string
+	message string
+}
+
+type dedupEntry struct {
+	start    time.Time
+	repeated int
+	// 最近一条被抑制的记录，用于输出汇总
+	core   zapcore.Core
+	ent    zapcore.Entry
+	fields []zapcore.Field
+}
+
+// dedupState 由同一配置派生的所有 dedupCore 共享
+type dedupState struct {
+	mu      sync.Mutex
+	window  time.Duration
+	maxKeys int
+	entries map[dedupKey]*dedupEntry
+}
+
+// dedupCore 按级别、logger 名称、调用位置与消息去重，字段不参与比较
+type dedupCore struct {
+	zapcore.Core
+	state *dedupState
+}
+
+// newDedupCore 包装 core 并定期输出到期的汇总，返回的函数输出剩余汇总后停止
+func newDedupCore(core zapcore.Core, opt DedupOption) (zapcore.Core, func()) {
+	if opt.Window <= 0 {
+		opt.Window = time.Second
+	}
+	if opt.MaxKeys <= 0 {
+		opt.MaxKeys = 10000
+	}
+	state := &dedupState{window: opt.Window, maxKeys: opt.MaxKeys, entries: make(map[dedupKey]*dedupEntry)}
+
+	stopCh := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(opt.Window)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopCh:
+				state.flush(time.Time{})
+				return
+			case now := <-ticker.C:
+				state.flush(now)
+			}
+		}
+	}()
+
+	stop := func() {
+		close(stopCh)
+		<-done
+	}
+	return &dedupCore{Core: core, state: state}, stop
+}
+
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{Core: c.Core.With(fields), state: c.state}
+}
+
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *dedupCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	key := dedupKey{level: ent.Level, name: ent.LoggerName, message: ent.Message}
+	if ent.Caller.Defined {
+		key.caller = ent.Caller.String()
+	}
+
+	s := c.state
+	s.mu.Lock()
+	e, ok := s.entries[key]
+	if ok && ent.Time.Sub(e.start) < s.window {
+		e.repeated++
+		e.core, e.ent, e.fields = c.Core, ent, snapshotFields(fields)
+		s.mu.Unlock()
+		return nil
+	}
+
+	var summary *dedupEntry
+	if ok {
+		if e.repeated > 0 {
+			copied := *e
+			summary = &copied
+		}
+		e.start, e.repeated, e.core, e.fields = ent.Time, 0, nil, nil
+	} else if len(s.entries) < s.maxKeys {
+		s.entries[key] = &dedupEntry{start: ent.Time}
+	}
+	s.mu.Unlock()
+
+	if summary != nil {
+		summary.write()
+	}
+	return writeChecked(c.Core, ent, fields)
+}
+
+// flush 输出窗口已结束的汇总并移除对应的记录，now 为零值时输出全部
+func (s *dedupState) flush(now time.Time) {
+	var summaries []*dedupEntry
+	s.mu.Lock()
+	for key, e := range s.entries {
+		if !now.IsZero() && now.Sub(e.start) < s.window {
+			continue
+		}
+		if e.repeated > 0 {
+			summaries = append(summaries, e)
+		}
+		delete(s.entries, key)
+	}
+	s.mu.Unlock()
+
+	for _, e := range summaries {
+		e.write()
+	}
+}
+
+func (e *dedupEntry) write() {
+	ent := e.ent
+	ent.Message = fmt.Sprintf("%s (repeated %d times)", ent.Message, e.repeated)
+	fields := append(e.fields[:len(e.fields):len(e.fields)], zap.Int("repeated", e.repeated))
+	_ = writeChecked(e.core, ent, fields)
+}
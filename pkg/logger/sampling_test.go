@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSampling(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	swap := newSwapCore()
+	swap.swap(newSamplingCore(obs, SamplingOption{Tick: time.Hour, Initial: 2, Thereafter: 3}), nil)
+	l := zap.New(swap)
+
+	before := testutil.ToFloat64(sampledRecordsTotal.WithLabelValues("error"))
+	for i := 0; i < 8; i++ {
+		l.Error("dial tcp 127.0.0.1:6379: connect: connection refused")
+	}
+	l.Error("another message")
+
+	// 前 2 条全部输出，之后每 3 条输出 1 条：第 1、2、5、8 条
+	if n := logs.FilterMessage("dial tcp 127.0.0.1:6379: connect: connection refused").Len(); n != 4 {
+		t.Errorf("sampled records = %d, want 4", n)
+	}
+	if logs.FilterMessage("another message").Len() != 1 {
+		t.Error("different messages are sampled separately")
+	}
+	if d := testutil.ToFloat64(sampledRecordsTotal.WithLabelValues("error")) - before; d != 4 {
+		t.Errorf("dropped by sampling = %v, want 4", d)
+	}
+}
+
+func TestDedup(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	core, stop := newDedupCore(obs, DedupOption{Window: time.Hour})
+	l := zap.New(core).Named("redis")
+
+	for i := 0; i < 1000; i++ {
+		l.Error("connection failed", zap.Error(errors.New("refused")), zap.Int("attempt", i))
+	}
+	l.Warn("connection failed")
+	if logs.Len() != 2 {
+		t.Fatalf("records within window = %d, want first occurrence per level", logs.Len())
+	}
+
+	// 停止时输出窗口内的汇总，携带最后一条被抑制记录的字段
+	stop()
+	summaries := logs.FilterField(zap.Int("repeated", 999)).All()
+	if len(summaries) != 1 {
+		t.Fatalf("records = %+v", logs.All())
+	}
+	got := summaries[0]
+	if got.Message != "connection failed (repeated 999 times)" || got.LoggerName != "redis" ||
+		got.Level != zapcore.ErrorLevel || got.ContextMap()["attempt"] != int64(999) {
+		t.Errorf("summary = %+v", got)
+	}
+}
+
+func TestDedupWindow(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	core, stop := newDedupCore(obs, DedupOption{Window: time.Hour})
+	defer stop()
+
+	now := time.Now()
+	write := func(at time.Time) {
+		_ = core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Time: at, Message: "timeout"}, nil)
+	}
+	write(now)
+	write(now.Add(time.Minute))
+	write(now.Add(2 * time.Minute))
+	// 窗口结束后的第一条先触发汇总，再正常输出并开启新窗口
+	write(now.Add(2 * time.Hour))
+
+	var got []string
+	for _, e := range logs.All() {
+		got = append(got, e.Message)
+	}
+	want := []string{"timeout", "timeout (repeated 2 times)", "timeout"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("messages = %v, want %v", got, want)
+	}
+}
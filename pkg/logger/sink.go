@@ -0,0 +1,178 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/natefinch/lumberjack"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap/zapcore"
+)
+
+// logger.sinks 中 type 的取值
+const (
+	SinkConsole   = "console"
+	SinkFile      = "file"
+	SinkErrorFile = "error_file" // 仅输出 error 及以上级别的文件，默认 error.log
+	SinkSyslog    = "syslog"
+	SinkHTTP      = "http"
+)
+
+var sinkDroppedRecordsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "goboot",
+	Subsystem: "logger",
+	Name:      "sink_dropped_records_total",
+	Help:      "Number of log records dropped by network sinks (syslog, http) by sink and level.",
+}, []string{"sink", "level"})
+
+func init() {
+	prometheus.MustRegister(sinkDroppedRecordsTotal)
+}
+
+// SinkOption 是 logger.sinks 的一项，字段按 type 选用
+type SinkOption struct {
+	Type    string `mapstructure:"type"`
+	Level   string `mapstructure:"level"`   // 该输出的最低级别，为空时只受 logger.level / logger.levels 控制
+	Encoder string `mapstructure:"encoder"` // json, console, logfmt。console 默认 console，其余默认 json
+
+	// 写缓冲（console、file、error_file），buffer_size 为 0 时不缓冲
+	BufferSize    int           `mapstructure:"buffer_size"`    // 字节数
+	FlushInterval time.Duration `mapstructure:"flush_interval"` // 缓冲与 http 批量的刷新间隔。默认: 1s
+
+	// file、error_file
+	FileName   string `mapstructure:"file_name"`
+	MaxSizeMB  int    `mapstructure:"max_size_mb"`
+	MaxAgeDays int    `mapstructure:"max_age_days"`
+	MaxBackups int    `mapstructure:"max_backups"`
+	Compress   bool   `mapstructure:"compress"`
+
+	// syslog
+	Network  string `mapstructure:"network"`  // unix, unixgram, udp, tcp。默认: unixgram
+	Address  string `mapstructure:"address"`  // 默认: /dev/log
+	Tag      string `mapstructure:"tag"`      // 默认为进程名
+	Facility string `mapstructure:"facility"` // user, daemon, local0 ~ local7。默认: local0
+
+	// http
+	URL       string            `mapstructure:"url"`
+	Format    string            `mapstructure:"format"` // loki, elasticsearch, ndjson。默认: loki
+	Index     string            `mapstructure:"index"`  // elasticsearch 索引
+	Labels    map[string]string `mapstructure:"labels"` // loki stream labels
+	Headers   map[string]string `mapstructure:"headers"`
+	BatchSize int               `mapstructure:"batch_size"` // 达到条数立即推送。默认: 100
+	Timeout   time.Duration     `mapstructure:"timeout"`    // 默认: 5s
+}
+
+// sinkOptions 返回配置的 sinks；未配置时由 console_enabled / file_enabled 等旧配置生成
+func (o *Option) sinkOptions() []SinkOption {
+	if len(o.Sinks) > 0 {
+		return o.Sinks
+	}
+	sinks := make([]SinkOption, 0, 2)
+	if o.ConsoleEnabled {
+		sinks = append(sinks, SinkOption{Type: SinkConsole})
+	}
+	if o.FileEnabled {
+		sinks = append(sinks, SinkOption{
+			Type:       SinkFile,
+			FileName:   o.FileName,
+			MaxSizeMB:  o.MaxSizeMB,
+			MaxAgeDays: o.MaxAgeDays,
+			Compress:   o.Compress,
+		})
+	}
+	return sinks
+}
+
+// buildSink 创建单个输出的 core 及其关闭函数
+func buildSink(opt SinkOption) (zapcore.Core, func(), error) {
+	typ := strings.ToLower(opt.Type)
+
+	level := zapcore.DebugLevel
+	if typ == SinkErrorFile {
+		level = zapcore.ErrorLevel
+	}
+	if opt.Level != "" {
+		l, err := parseLevel(opt.Level)
+		if err != nil {
+			return nil, nil, err
+		}
+		level = l
+	}
+
+	encoderName := opt.Encoder
+	if encoderName == "" {
+		encoderName = EncoderJSON
+		if typ == SinkConsole {
+			encoderName = EncoderConsole
+		}
+	}
+	encoder, err := newEncoder(encoderName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch typ {
+	case SinkConsole:
+		ws, closer := bufferSyncer(zapcore.Lock(os.Stdout), opt)
+		return zapcore.NewCore(encoder, ws, level), closer, nil
+	case SinkFile, SinkErrorFile:
+		fileName := opt.FileName
+		if fileName == "" {
+			fileName = "app.log"
+			if typ == SinkErrorFile {
+				fileName = "error.log"
+			}
+		}
+		lj := &lumberjack.Logger{
+			Filename:   fileName,
+			MaxSize:    opt.MaxSizeMB,
+			MaxAge:     opt.MaxAgeDays,
+			MaxBackups: opt.MaxBackups,
+			Compress:   opt.Compress,
+		}
+		ws, flush := bufferSyncer(zapcore.AddSync(lj), opt)
+		return zapcore.NewCore(encoder, ws, level), func() {
+			flush()
+			_ = lj.Close()
+		}, nil
+	case SinkSyslog:
+		return newSyslogCore(opt, encoder, level)
+	case SinkHTTP:
+		return newHTTPCore(opt, encoder, level)
+	default:
+		return nil, nil, fmt.Errorf("unknown log sink type %q", opt.Type)
+	}
+}
+
+// bufferSyncer 按 buffer_size 包装写缓冲，返回的函数刷新并停止缓冲
+func bufferSyncer(ws zapcore.WriteSyncer, opt SinkOption) (zapcore.WriteSyncer, func()) {
+	if opt.BufferSize <= 0 {
+		return ws, func() { _ = ws.Sync() }
+	}
+	buffered := &zapcore.BufferedWriteSyncer{
+		WS:            ws,
+		Size:          opt.BufferSize,
+		FlushInterval: opt.FlushInterval,
+	}
+	return buffered, func() { _ = buffered.Stop() }
+}
+
+// buildSinks 创建所有输出，任一失败时关闭已创建的输出
+func buildSinks(sinks []SinkOption) ([]zapcore.Core, []func(), error) {
+	cores := make([]zapcore.Core, 0, len(sinks))
+	closers := make([]func(), 0, len(sinks))
+	for i, s := range sinks {
+		core, closer, err := buildSink(s)
+		if err != nil {
+			for _, c := range closers {
+				c()
+			}
+			return nil, nil, fmt.Errorf("logger.sinks[%d] (%s): %w", i, s.Type, err)
+		}
+		cores = append(cores, core)
+		closers = append(closers, closer)
+	}
+	return cores, closers, nil
+}
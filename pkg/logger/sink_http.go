@@ -0,0 +1,275 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	HTTPFormatLoki          = "loki"
+	HTTPFormatElasticsearch = "elasticsearch"
+	HTTPFormatNDJSON        = "ndjson"
+)
+
+type httpRecord struct {
+	time  time.Time
+	level zapcore.Level
+	line  []byte
+}
+
+// httpSink 批量推送日志，达到 batch_size 或每隔 flush_interval 推送一次。
+// 推送失败的批次被丢弃，积压超过 10 倍 batch_size 时丢弃最早的记录，避免拖垮业务；
+// 丢弃的条数见指标 goboot_logger_sink_dropped_records_total{sink="http"}
+type httpSink struct {
+	opt     SinkOption
+	client  *http.Client
+	mu      sync.Mutex
+	pending []httpRecord
+	sendMu  sync.Mutex
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+func newHTTPCore(opt SinkOption, enc zapcore.Encoder, level zapcore.Level) (zapcore.Core, func(), error) {
+	if opt.URL == "" {
+		return nil, nil, fmt.Errorf("http log sink requires url")
+	}
+	if opt.Format == "" {
+		opt.Format = HTTPFormatLoki
+	}
+	switch opt.Format {
+	case HTTPFormatLoki, HTTPFormatNDJSON:
+	case HTTPFormatElasticsearch:
+		if opt.Index == "" {
+			return nil, nil, fmt.Errorf("elasticsearch log sink requires index")
+		}
+		if opt.Encoder != "" && opt.Encoder != EncoderJSON {
+			return nil, nil, fmt.Errorf("elasticsearch log sink requires json encoder")
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown http log format %q, expected loki, elasticsearch or ndjson", opt.Format)
+	}
+	if opt.BatchSize <= 0 {
+		opt.BatchSize = 100
+	}
+	if opt.FlushInterval <= 0 {
+		opt.FlushInterval = time.Second
+	}
+	if opt.Timeout <= 0 {
+		opt.Timeout = 5 * time.Second
+	}
+
+	s := &httpSink{
+		opt:     opt,
+		client:  &http.Client{Timeout: opt.Timeout},
+		flushCh: make(chan struct{}, 1),
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	go s.loop()
+
+	return &httpCore{LevelEnabler: level, enc: enc, sink: s}, s.close, nil
+}
+
+func (s *httpSink) add(rec httpRecord) {
+	s.mu.Lock()
+	if limit := s.opt.BatchSize * 10; len(s.pending) >= limit {
+		n := len(s.pending) - limit + 1
+		countDropped(s.pending[:n])
+		s.pending = s.pending[n:]
+	}
+	s.pending = append(s.pending, rec)
+	full := len(s.pending) >= s.opt.BatchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *httpSink) loop() {
+	defer close(s.doneCh)
+	ticker := time.NewTicker(s.opt.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			s.flush()
+			return
+		case <-ticker.C:
+			s.flush()
+		case <-s.flushCh:
+			s.flush()
+		}
+	}
+}
+
+// flush 推送所有积压的记录
+func (s *httpSink) flush() {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	for {
+		s.mu.Lock()
+		n := min(len(s.pending), s.opt.BatchSize)
+		batch := s.pending[:n:n]
+		s.pending = s.pending[n:]
+		s.mu.Unlock()
+
+		if n == 0 {
+			return
+		}
+		if err := s.send(batch); err != nil {
+			countDropped(batch)
+		}
+	}
+}
+
+// countDropped 将丢弃的记录计入 sink_dropped_records_total
+func countDropped(records []httpRecord) {
+	for _, rec := range records {
+		sinkDroppedRecordsTotal.WithLabelValues(SinkHTTP, rec.level.String()).Inc()
+	}
+}
+
+func (s *httpSink) send(batch []httpRecord) error {
+	body, contentType, err := s.encodeBatch(batch)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.opt.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.opt.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range s.opt.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *httpSink) encodeBatch(batch []httpRecord) ([]byte, string, error) {
+	var buf bytes.Buffer
+	switch s.opt.Format {
+	case HTTPFormatLoki:
+		// 按级别分 stream，便于在 Loki 中按 level 过滤
+		streams := make(map[zapcore.Level][][2]string)
+		order := make([]zapcore.Level, 0, 2)
+		for _, rec := range batch {
+			if _, ok := streams[rec.level]; !ok {
+				order = append(order, rec.level)
+			}
+			streams[rec.level] = append(streams[rec.level], [2]string{
+				strconv.FormatInt(rec.time.UnixNano(), 10),
+				strings.TrimRight(string(rec.line), "\n"),
+			})
+		}
+
+		type lokiStream struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		}
+		payload := struct {
+			Streams []lokiStream `json:"streams"`
+		}{}
+		for _, level := range order {
+			labels := make(map[string]string, len(s.opt.Labels)+1)
+			for k, v := range s.opt.Labels {
+				labels[k] = v
+			}
+			labels["level"] = level.String()
+			payload.Streams = append(payload.Streams, lokiStream{Stream: labels, Values: streams[level]})
+		}
+		if err := json.NewEncoder(&buf).Encode(payload); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "application/json", nil
+	case HTTPFormatElasticsearch:
+		action, _ := json.Marshal(map[string]interface{}{"index": map[string]string{"_index": s.opt.Index}})
+		for _, rec := range batch {
+			buf.Write(action)
+			buf.WriteByte('\n')
+			buf.Write(bytes.TrimRight(rec.line, "\n"))
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes(), "application/x-ndjson", nil
+	default:
+		for _, rec := range batch {
+			buf.Write(bytes.TrimRight(rec.line, "\n"))
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes(), "application/x-ndjson", nil
+	}
+}
+
+func (s *httpSink) close() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+type httpCore struct {
+	zapcore.LevelEnabler
+	enc  zapcore.Encoder
+	sink *httpSink
+}
+
+func (c *httpCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &httpCore{LevelEnabler: c.LevelEnabler, enc: enc, sink: c.sink}
+}
+
+func (c *httpCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *httpCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	line := append([]byte(nil), buf.Bytes()...)
+	buf.Free()
+
+	c.sink.add(httpRecord{time: ent.Time, level: ent.Level, line: line})
+	if ent.Level > zapcore.ErrorLevel {
+		// panic / fatal 之后进程可能退出，立即推送
+		c.sink.flush()
+	}
+	return nil
+}
+
+// Sync 立即推送积压的记录
+func (c *httpCore) Sync() error {
+	c.sink.flush()
+	return nil
+}
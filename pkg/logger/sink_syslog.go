@@ -0,0 +1,283 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogSeverity 将日志级别映射为 syslog severity（RFC 5424）
+func syslogSeverity(l zapcore.Level) int {
+	switch l {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return 2
+	default:
+		return 1
+	}
+}
+
+const (
+	syslogBufferSize   = 1024             // 等待发送的记录条数，超出后丢弃新记录
+	syslogMaxBackoff   = 30 * time.Second // 重连间隔上限
+	syslogInitBackoff  = 500 * time.Millisecond
+	syslogDialTimeout  = 5 * time.Second
+	syslogFlushTimeout = 5 * time.Second
+)
+
+type syslogPacket struct {
+	level zapcore.Level
+	data  []byte
+	done  chan struct{} // 非空时为 Sync 的标记，写到此处时关闭
+}
+
+// syslogWriter 以 RFC 3164 格式发送消息。写入只进入有界队列，由后台协程发送，
+// 连接断开后按指数退避重连，不阻塞业务；队列满或发送失败的记录计入 sink_dropped_records_total
+type syslogWriter struct {
+	network  string
+	address  string
+	tag      string
+	hostname string
+	conn     net.Conn // 仅由后台协程访问
+	queue    chan syslogPacket
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	stopOnce sync.Once
+}
+
+func (w *syslogWriter) dial() error {
+	conn, err := net.DialTimeout(w.network, w.address, syslogDialTimeout)
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	return nil
+}
+
+func (w *syslogWriter) write(level zapcore.Level, priority int, t time.Time, msg []byte) {
+	line := strings.TrimRight(string(msg), "\n")
+	var packet string
+	if w.network == "unix" || w.network == "unixgram" {
+		// 本地 syslog 守护进程自行补充主机名
+		packet = fmt.Sprintf("<%d>%s %s[%d]: %s", priority, t.Format(time.Stamp), w.tag, os.Getpid(), line)
+	} else {
+		packet = fmt.Sprintf("<%d>%s %s %s[%d]: %s", priority, t.Format(time.Stamp), w.hostname, w.tag, os.Getpid(), line)
+	}
+	if w.network == "tcp" || w.network == "unix" {
+		packet += "\n"
+	}
+
+	select {
+	case w.queue <- syslogPacket{level: level, data: []byte(packet)}:
+	default:
+		sinkDroppedRecordsTotal.WithLabelValues(SinkSyslog, level.String()).Inc()
+	}
+}
+
+func (w *syslogWriter) loop() {
+	defer close(w.doneCh)
+	backoff := syslogInitBackoff
+	for {
+		var p syslogPacket
+		select {
+		case <-w.stopCh:
+			w.drain()
+			return
+		case p = <-w.queue:
+		}
+		if p.done != nil {
+			close(p.done)
+			continue
+		}
+
+		// 连接不可用时按指数退避重连，期间新记录在队列中等待
+		for w.conn == nil {
+			if err := w.dial(); err == nil {
+				backoff = syslogInitBackoff
+				break
+			}
+			select {
+			case <-w.stopCh:
+				sinkDroppedRecordsTotal.WithLabelValues(SinkSyslog, p.level.String()).Inc()
+				w.drain()
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, syslogMaxBackoff)
+		}
+		w.send(p)
+	}
+}
+
+// send 写入一条记录，写入失败时重连并重试一次
+func (w *syslogWriter) send(p syslogPacket) {
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.conn == nil && w.dial() != nil {
+			break
+		}
+		if _, err := w.conn.Write(p.data); err == nil {
+			return
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	sinkDroppedRecordsTotal.WithLabelValues(SinkSyslog, p.level.String()).Inc()
+}
+
+// drain 在关闭时尽量发送队列中剩余的记录，连接不可用时不再重连
+func (w *syslogWriter) drain() {
+	for {
+		select {
+		case p := <-w.queue:
+			switch {
+			case p.done != nil:
+				close(p.done)
+			case w.conn != nil:
+				w.send(p)
+			default:
+				sinkDroppedRecordsTotal.WithLabelValues(SinkSyslog, p.level.String()).Inc()
+			}
+		default:
+			return
+		}
+	}
+}
+
+// flush 等待此前写入的记录发送完成，最多等待 syslogFlushTimeout
+func (w *syslogWriter) flush() {
+	done := make(chan struct{})
+	timeout := time.NewTimer(syslogFlushTimeout)
+	defer timeout.Stop()
+	select {
+	case w.queue <- syslogPacket{done: done}:
+	case <-w.doneCh:
+		return
+	case <-timeout.C:
+		return
+	}
+	select {
+	case <-done:
+	case <-w.doneCh:
+	case <-timeout.C:
+	}
+}
+
+func (w *syslogWriter) close() {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+		<-w.doneCh
+		if w.conn != nil {
+			_ = w.conn.Close()
+			w.conn = nil
+		}
+	})
+}
+
+// syslogCore 按每条日志的级别计算 syslog priority
+type syslogCore struct {
+	zapcore.LevelEnabler
+	enc      zapcore.Encoder
+	writer   *syslogWriter
+	facility int
+}
+
+func newSyslogCore(opt SinkOption, enc zapcore.Encoder, level zapcore.Level) (zapcore.Core, func(), error) {
+	network, address := opt.Network, opt.Address
+	if network == "" {
+		network = "unixgram"
+	}
+	if address == "" {
+		address = "/dev/log"
+	}
+	switch network {
+	case "unix", "unixgram", "udp", "tcp":
+	default:
+		return nil, nil, fmt.Errorf("unsupported syslog network %q", network)
+	}
+
+	facility := syslogFacilities["local0"]
+	if opt.Facility != "" {
+		f, ok := syslogFacilities[strings.ToLower(opt.Facility)]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown syslog facility %q", opt.Facility)
+		}
+		facility = f
+	}
+
+	tag := opt.Tag
+	if tag == "" {
+		tag = filepath.Base(os.Args[0])
+	}
+	hostname, _ := os.Hostname()
+
+	w := &syslogWriter{
+		network:  network,
+		address:  address,
+		tag:      tag,
+		hostname: hostname,
+		queue:    make(chan syslogPacket, syslogBufferSize),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+	// 启动时连接失败视为配置错误，之后的断开由后台协程重连
+	if err := w.dial(); err != nil {
+		return nil, nil, fmt.Errorf("failed to connect syslog %s %s: %w", network, address, err)
+	}
+	go w.loop()
+
+	core := &syslogCore{LevelEnabler: level, enc: enc, writer: w, facility: facility}
+	return core, w.close, nil
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &syslogCore{LevelEnabler: c.LevelEnabler, enc: enc, writer: c.writer, facility: c.facility}
+}
+
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+	c.writer.write(ent.Level, c.facility*8+syslogSeverity(ent.Level), ent.Time, buf.Bytes())
+	if ent.Level > zapcore.ErrorLevel {
+		// panic / fatal 之后进程可能退出，等待发送完成
+		c.writer.flush()
+	}
+	return nil
+}
+
+// Sync 不等待后台发送，避免连接断开时阻塞调用方；关闭时发送队列中剩余的记录
+func (c *syslogCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,305 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func newSinkLogger(t *testing.T, opt SinkOption) (*zap.Logger, func()) {
+	t.Helper()
+	core, closer, err := buildSink(opt)
+	if err != nil {
+		t.Fatalf("build sink: %v", err)
+	}
+	return zap.New(core), closer
+}
+
+func TestSyslogSink(t *testing.T) {
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer udp.Close()
+
+	socket := filepath.Join(t.TempDir(), "log.sock")
+	unixgram, err := net.ListenPacket("unixgram", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unixgram.Close()
+
+	cases := []struct {
+		network, address string
+		conn             net.PacketConn
+		hostname         bool
+	}{
+		{"udp", udp.LocalAddr().String(), udp, true},
+		{"unixgram", socket, unixgram, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.network, func(t *testing.T) {
+			l, closer := newSinkLogger(t, SinkOption{
+				Type:     SinkSyslog,
+				Network:  tc.network,
+				Address:  tc.address,
+				Tag:      "goboot",
+				Facility: "local3",
+				Encoder:  EncoderLogfmt,
+			})
+			defer closer()
+			l.Warn("disk almost full", zap.Int("percent", 91))
+
+			buf := make([]byte, 2048)
+			_ = tc.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			n, _, err := tc.conn.ReadFrom(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			packet := string(buf[:n])
+
+			// local3(19) * 8 + warning(4)
+			if !strings.HasPrefix(packet, "<156>") {
+				t.Errorf("packet = %q, want priority <156>", packet)
+			}
+			if !strings.Contains(packet, " goboot[") || !strings.Contains(packet, `msg="disk almost full" percent=91`) {
+				t.Errorf("packet = %q", packet)
+			}
+			hostname, _ := os.Hostname()
+			if got := strings.Contains(packet, " "+hostname+" goboot["); got != tc.hostname {
+				t.Errorf("hostname present = %v, want %v: %q", got, tc.hostname, packet)
+			}
+		})
+	}
+}
+
+func TestSyslogSinkReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+
+	l, closer := newSinkLogger(t, SinkOption{Type: SinkSyslog, Network: "tcp", Address: addr, Encoder: EncoderLogfmt})
+	defer closer()
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info("first")
+	readSyslogLine(t, bufio.NewReader(conn), "first")
+
+	// 服务端不可用时写日志不阻塞
+	_ = conn.Close()
+	_ = ln.Close()
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		l.Info("during outage")
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("logging during outage took %s, want non-blocking", elapsed)
+	}
+
+	// 服务端恢复后后台重连，之后的记录正常送达
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("cannot listen on %s again: %v", addr, err)
+	}
+	defer ln.Close()
+	l.Info("after reconnect")
+	_ = ln.(*net.TCPListener).SetDeadline(time.Now().Add(10 * time.Second))
+	conn, err = ln.Accept()
+	if err != nil {
+		t.Fatalf("syslog sink did not reconnect: %v", err)
+	}
+	defer conn.Close()
+	readSyslogLine(t, bufio.NewReader(conn), "after reconnect")
+}
+
+// readSyslogLine 读取直到出现包含 want 的一行
+func readSyslogLine(t *testing.T, r *bufio.Reader, want string) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		line, err := r.ReadString('\n')
+		if strings.Contains(line, want) {
+			return
+		}
+		if err != nil {
+			t.Fatalf("read syslog: %v", err)
+		}
+	}
+	t.Fatalf("no syslog line containing %q", want)
+}
+
+func TestHTTPSink(t *testing.T) {
+	type request struct {
+		contentType string
+		body        string
+	}
+	received := make(chan request, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- request{contentType: r.Header.Get("Content-Type"), body: string(body)}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	t.Run("loki", func(t *testing.T) {
+		l, closer := newSinkLogger(t, SinkOption{
+			Type:          SinkHTTP,
+			URL:           srv.URL,
+			Labels:        map[string]string{"app": "goboot"},
+			BatchSize:     2,
+			FlushInterval: time.Hour,
+		})
+		defer closer()
+		l.Info("first")
+		l.Error("second")
+
+		var req request
+		select {
+		case req = <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for batch")
+		}
+
+		var payload struct {
+			Streams []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string       `json:"values"`
+			} `json:"streams"`
+		}
+		if err := json.Unmarshal([]byte(req.body), &payload); err != nil {
+			t.Fatalf("decode %q: %v", req.body, err)
+		}
+		if len(payload.Streams) != 2 {
+			t.Fatalf("streams = %+v, want one per level", payload.Streams)
+		}
+		info := payload.Streams[0]
+		if info.Stream["app"] != "goboot" || info.Stream["level"] != "info" || len(info.Values) != 1 {
+			t.Errorf("info stream = %+v", info)
+		}
+		if !strings.Contains(info.Values[0][1], `"msg":"first"`) {
+			t.Errorf("line = %q", info.Values[0][1])
+		}
+	})
+
+	t.Run("elasticsearch", func(t *testing.T) {
+		l, closer := newSinkLogger(t, SinkOption{
+			Type:          SinkHTTP,
+			URL:           srv.URL,
+			Format:        HTTPFormatElasticsearch,
+			Index:         "goboot-logs",
+			FlushInterval: time.Hour,
+		})
+		l.Info("bulk", zap.String("k", "v"))
+		// 关闭时推送剩余记录
+		closer()
+
+		var req request
+		select {
+		case req = <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for batch")
+		}
+		if req.contentType != "application/x-ndjson" {
+			t.Errorf("content type = %q", req.contentType)
+		}
+		scanner := bufio.NewScanner(strings.NewReader(req.body))
+		var lines []string
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		if len(lines) != 2 || lines[0] != `{"index":{"_index":"goboot-logs"}}` || !strings.Contains(lines[1], `"k":"v"`) {
+			t.Errorf("bulk body = %q", req.body)
+		}
+	})
+}
+
+func TestFileSinks(t *testing.T) {
+	dir := t.TempDir()
+	opt := &Option{Sinks: []SinkOption{
+		{Type: SinkFile, FileName: filepath.Join(dir, "app.log"), Encoder: EncoderLogfmt},
+		{Type: SinkErrorFile, FileName: filepath.Join(dir, "error.log"), BufferSize: 4096, FlushInterval: time.Hour},
+	}}
+	cores, closers, err := buildSinks(opt.sinkOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := zap.New(zapcore.NewTee(cores...))
+	l.Info("started", zap.String("addr", "0.0.0.0:8080"))
+	l.Error("request failed", zap.Duration("elapsed", 1500*time.Millisecond))
+	for _, c := range closers {
+		c()
+	}
+
+	app, _ := os.ReadFile(filepath.Join(dir, "app.log"))
+	lines := strings.Split(strings.TrimSpace(string(app)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "level=info msg=started addr=0.0.0.0:8080") ||
+		!strings.Contains(lines[1], `msg="request failed" elapsed=1.5s`) {
+		t.Errorf("app.log = %q", app)
+	}
+
+	errors, _ := os.ReadFile(filepath.Join(dir, "error.log"))
+	if strings.Contains(string(errors), "started") || !strings.Contains(string(errors), `"msg":"request failed"`) {
+		t.Errorf("error.log = %q", errors)
+	}
+}
+
+func TestHTTPSinkDropMetric(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	l, closer := newSinkLogger(t, SinkOption{Type: SinkHTTP, URL: srv.URL, Format: HTTPFormatNDJSON, BatchSize: 10, FlushInterval: time.Hour})
+	defer closer()
+
+	before := testutil.ToFloat64(sinkDroppedRecordsTotal.WithLabelValues(SinkHTTP, "warn"))
+	l.Warn("first")
+	l.Warn("second")
+	_ = l.Sync()
+	if d := testutil.ToFloat64(sinkDroppedRecordsTotal.WithLabelValues(SinkHTTP, "warn")) - before; d != 2 {
+		t.Errorf("dropped = %v, want 2", d)
+	}
+}
+
+func TestHTTPSinkFlushesOnPanic(t *testing.T) {
+	received := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- string(body)
+	}))
+	defer srv.Close()
+
+	l, closer := newSinkLogger(t, SinkOption{Type: SinkHTTP, URL: srv.URL, Format: HTTPFormatNDJSON, BatchSize: 100, FlushInterval: time.Hour})
+	defer closer()
+
+	// panic / fatal 级别写入后立即推送，不等待 Sync 或 flush_interval
+	func() {
+		defer func() { _ = recover() }()
+		l.Panic("boom")
+	}()
+	select {
+	case body := <-received:
+		if !strings.Contains(body, "boom") {
+			t.Errorf("body = %q", body)
+		}
+	default:
+		t.Fatal("panic record not pushed before the logger returned")
+	}
+}
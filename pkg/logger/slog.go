@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// slogHandler 将 log/slog 的记录写入 zap core，与 zap logger 共用级别、sink 与脱敏等处理
+type slogHandler struct {
+	core zapcore.Core
+	name string
+}
+
+// NewSlogHandler 返回写入 l 的 slog.Handler，记录的 logger 名称即 l 的名称，按模块级别过滤
+func NewSlogHandler(l *zap.Logger) slog.Handler {
+	return &slogHandler{core: l.Core(), name: l.Name()}
+}
+
+// Slog 返回写入全局 logger 的 *slog.Logger，配置重载后依然有效
+func Slog() *slog.Logger {
+	return slog.New(NewSlogHandler(globalLogger))
+}
+
+// zapLevel 将 slog 级别映射到不低于它的 zap 级别，slog 的自定义级别向下取整
+func zapLevel(l slog.Level) zapcore.Level {
+	switch {
+	case l >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case l >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case l >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return h.core.Enabled(zapLevel(l))
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	ent := zapcore.Entry{
+		Level:      zapLevel(r.Level),
+		Time:       r.Time,
+		LoggerName: h.name,
+		Message:    r.Message,
+	}
+	if ent.Time.IsZero() {
+		ent.Time = time.Now()
+	}
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ent.Caller = zapcore.NewEntryCaller(r.PC, frame.File, frame.Line, true)
+	}
+
+	ce := h.core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+	fields := make([]zapcore.Field, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, a)
+		return true
+	})
+	ce.Write(fields...)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]zapcore.Field, 0, len(attrs))
+	for _, a := range attrs {
+		fields = appendAttr(fields, a)
+	}
+	return &slogHandler{core: h.core.With(fields), name: h.name}
+}
+
+// WithGroup 之后的属性嵌套在 name 下
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{core: h.core.With([]zapcore.Field{zap.Namespace(name)}), name: h.name}
+}
+
+// appendAttr 按 slog 的约定转换属性：忽略空属性，key 为空的分组展开到上一层
+func appendAttr(fields []zapcore.Field, a slog.Attr) []zapcore.Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		if len(group) == 0 {
+			return fields
+		}
+		if a.Key == "" {
+			for _, ga := range group {
+				fields = appendAttr(fields, ga)
+			}
+			return fields
+		}
+		return append(fields, zap.Object(a.Key, slogGroup(group)))
+	case slog.KindString:
+		return append(fields, zap.String(a.Key, a.Value.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(a.Key, a.Value.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(a.Key, a.Value.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(a.Key, a.Value.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(a.Key, a.Value.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(a.Key, a.Value.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(a.Key, a.Value.Time()))
+	default:
+		if err, ok := a.Value.Any().(error); ok {
+			return append(fields, zap.NamedError(a.Key, err))
+		}
+		return append(fields, zap.Any(a.Key, a.Value.Any()))
+	}
+}
+
+type slogGroup []slog.Attr
+
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range appendAttr(nil, slog.Attr{Value: slog.GroupValue(g...)}) {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// stdLogWriter 接收标准库 log 的输出。写入时不 Sync，log.Fatal 退出前异步缓冲中的记录可能丢失，
+// 需要退出进程时应使用 zap 的 Fatal，由各 core 在 fatal 级别写出后 Sync
+type stdLogWriter struct {
+	logger *zap.Logger
+	level  zapcore.Level
+}
+
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimSuffix(p, []byte("\n")))
+	if ce := w.logger.Check(w.level, msg); ce != nil {
+		ce.Write()
+	}
+	return len(p), nil
+}
+
+// RedirectStdLog 将标准库 log 的输出按 level 写入 l，返回恢复原有输出的函数
+func RedirectStdLog(l *zap.Logger, level zapcore.Level) func() {
+	flags, prefix, writer := log.Flags(), log.Prefix(), log.Writer()
+	log.SetFlags(0)
+	log.SetPrefix("")
+	// 调用方 -> log.Printf 等 -> log.output -> stdLogWriter.Write
+	log.SetOutput(&stdLogWriter{logger: l.WithOptions(zap.AddCallerSkip(3)), level: level})
+	return func() {
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		log.SetOutput(writer)
+	}
+}
+
+// installStdBridges 按配置将 slog.Default() 与标准库 log 接入全局 logger，仅在启动时生效
+func installStdBridges(opt *Option) error {
+	if opt.SlogDefault {
+		// slog.SetDefault 同时将标准库 log 转到 slog，RedirectStdLog 需在其后执行
+		slog.SetDefault(Slog())
+	}
+	if opt.RedirectStdLog {
+		level, err := parseLevel(opt.StdLogLevel)
+		if err != nil {
+			return fmt.Errorf("invalid logger.std_log_level: %w", err)
+		}
+		RedirectStdLog(globalLogger.Named("stdlog"), level)
+	}
+	return nil
+}
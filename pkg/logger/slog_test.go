@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSlogHandler(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	l := slog.New(NewSlogHandler(zap.New(obs).Named("lib")))
+
+	l.With("component", "cache").WithGroup("req").Warn("slow lookup",
+		"elapsed", 1500*time.Millisecond,
+		slog.Group("key", "id", 42, "hit", false),
+		"err", errors.New("timeout"),
+	)
+
+	if logs.Len() != 1 {
+		t.Fatalf("records = %d, want 1", logs.Len())
+	}
+	got := logs.All()[0]
+	if got.Level != zapcore.WarnLevel || got.LoggerName != "lib" || got.Message != "slow lookup" {
+		t.Errorf("entry = %+v", got.Entry)
+	}
+	if !got.Caller.Defined || !strings.HasSuffix(got.Caller.File, "slog_test.go") {
+		t.Errorf("caller = %+v, want the slog call site", got.Caller)
+	}
+
+	ctx := got.ContextMap()
+	req, _ := ctx["req"].(map[string]interface{})
+	key, _ := req["key"].(map[string]interface{})
+	if ctx["component"] != "cache" || req["elapsed"] != 1500*time.Millisecond || req["err"] != "timeout" ||
+		key["id"] != int64(42) || key["hit"] != false {
+		t.Errorf("fields = %#v", ctx)
+	}
+}
+
+func TestSlogLevels(t *testing.T) {
+	registry := newLevelRegistry()
+	if err := registry.configure("info", map[string]string{"lib": "error"}); err != nil {
+		t.Fatal(err)
+	}
+	obs, logs := observer.New(zapcore.DebugLevel)
+	core := newLevelCore(obs, registry)
+
+	root := slog.New(NewSlogHandler(zap.New(core)))
+	lib := slog.New(NewSlogHandler(zap.New(core).Named("lib")))
+
+	root.Debug("hidden")
+	root.Log(context.Background(), slog.LevelInfo+2, "custom level")
+	lib.Warn("filtered by module level")
+	lib.Error("kept")
+
+	var got []string
+	for _, e := range logs.All() {
+		got = append(got, e.Level.String()+":"+e.Message)
+	}
+	if strings.Join(got, ",") != "info:custom level,error:kept" {
+		t.Errorf("records = %v", got)
+	}
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	restore := RedirectStdLog(zap.New(obs, zap.AddCaller()).Named("stdlog"), zapcore.ErrorLevel)
+	log.Printf("无法连接到数据库: %v", errors.New("refused"))
+	restore()
+
+	if logs.Len() != 1 {
+		t.Fatalf("records = %d, want 1", logs.Len())
+	}
+	got := logs.All()[0]
+	if got.Level != zapcore.ErrorLevel || got.Message != "无法连接到数据库: refused" || got.LoggerName != "stdlog" {
+		t.Errorf("entry = %+v", got.Entry)
+	}
+	if !strings.HasSuffix(got.Caller.File, "slog_test.go") {
+		t.Errorf("caller = %s, want the log.Printf call site", got.Caller.File)
+	}
+}
@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// generation 是某一次配置创建出的 core 及其资源。写入持有读锁，
+// 替换后在写锁下标记 closed，保证正在进行的写入完成后才关闭旧的 sink
+type generation struct {
+	mu      sync.RWMutex
+	core    zapcore.Core
+	cleanup func()
+	closed  bool
+}
+
+// swapCore 位于稳定的 *zap.Logger 之后，配置重载时替换内部 core，
+// 各模块持有的 logger 无需重新注入即可使用新的级别、sink 与编码
+type swapCore struct {
+	current atomic.Pointer[generation]
+}
+
+func newSwapCore() *swapCore {
+	s := &swapCore{}
+	s.current.Store(&generation{core: zapcore.NewNopCore()})
+	return s
+}
+
+// swap 切换到新的 core，等待旧 core 上的写入完成后执行其 cleanup
+func (s *swapCore) swap(core zapcore.Core, cleanup func()) {
+	old := s.current.Swap(&generation{core: core, cleanup: cleanup})
+
+	old.mu.Lock()
+	old.closed = true
+	old.mu.Unlock()
+
+	_ = old.core.Sync()
+	if old.cleanup != nil {
+		old.cleanup()
+	}
+}
+
+// acquire 返回当前 generation 并持有其读锁，调用方需 RUnlock
+func (s *swapCore) acquire() *generation {
+	for {
+		gen := s.current.Load()
+		gen.mu.RLock()
+		if !gen.closed {
+			return gen
+		}
+		gen.mu.RUnlock()
+	}
+}
+
+func (s *swapCore) Enabled(l zapcore.Level) bool {
+	return s.current.Load().core.Enabled(l)
+}
+
+func (s *swapCore) With(fields []zapcore.Field) zapcore.Core {
+	return &swapWithCore{root: s, fields: fields}
+}
+
+func (s *swapCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if s.current.Load().core.Check(ent, nil) != nil {
+		return ce.AddCore(ent, s)
+	}
+	return ce
+}
+
+func (s *swapCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	gen := s.acquire()
+	defer gen.mu.RUnlock()
+	return writeChecked(gen.core, ent, fields)
+}
+
+func (s *swapCore) Sync() error {
+	gen := s.acquire()
+	defer gen.mu.RUnlock()
+	return gen.core.Sync()
+}
+
+// writeChecked 通过 Check 写入，确保按模块级别与各 sink 自身级别过滤
+func writeChecked(core zapcore.Core, ent zapcore.Entry, fields []zapcore.Field) error {
+	if ce := core.Check(ent, nil); ce != nil {
+		ce.Write(fields...)
+	}
+	return nil
+}
+
+// swapWithCore 是 swapCore.With 的结果，按 generation 缓存 core.With(fields)
+type swapWithCore struct {
+	root   *swapCore
+	fields []zapcore.Field
+	cached atomic.Pointer[withCache]
+}
+
+type withCache struct {
+	gen  *generation
+	core zapcore.Core
+}
+
+func (c *swapWithCore) coreFor(gen *generation) zapcore.Core {
+	if cached := c.cached.Load(); cached != nil && cached.gen == gen {
+		return cached.core
+	}
+	core := gen.core.With(c.fields)
+	c.cached.Store(&withCache{gen: gen, core: core})
+	return core
+}
+
+func (c *swapWithCore) Enabled(l zapcore.Level) bool {
+	return c.root.Enabled(l)
+}
+
+func (c *swapWithCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &swapWithCore{root: c.root, fields: merged}
+}
+
+func (c *swapWithCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.root.current.Load().core.Check(ent, nil) != nil {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *swapWithCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	gen := c.root.acquire()
+	defer gen.mu.RUnlock()
+	return writeChecked(c.coreFor(gen), ent, fields)
+}
+
+func (c *swapWithCore) Sync() error {
+	return c.root.Sync()
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// slowCore 在 Write 中阻塞，用于模拟替换 core 时仍在进行的写入
+type slowCore struct {
+	zapcore.Core
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *slowCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c *slowCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (c *slowCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	close(c.started)
+	<-c.release
+	return nil
+}
+
+func TestSwapCore(t *testing.T) {
+	swap := newSwapCore()
+	first, firstLogs := observer.New(zapcore.DebugLevel)
+	swap.swap(first, nil)
+
+	// 模块在构造时持有 logger 及其派生
+	held := zap.New(swap).Named("redis")
+	child := held.With(zap.String("addr", "127.0.0.1:6379"))
+	child.Info("before")
+
+	second, secondLogs := observer.New(zapcore.DebugLevel)
+	var closed atomic.Bool
+	swap.swap(second, func() { closed.Store(true) })
+	child.Info("after")
+
+	if firstLogs.Len() != 1 || secondLogs.Len() != 1 {
+		t.Fatalf("first = %d, second = %d records, want 1 each", firstLogs.Len(), secondLogs.Len())
+	}
+	got := secondLogs.All()[0]
+	if got.LoggerName != "redis" || got.ContextMap()["addr"] != "127.0.0.1:6379" {
+		t.Errorf("unexpected record after swap: %+v", got)
+	}
+
+	// 旧 core 上的写入完成之前不执行 cleanup
+	slow := &slowCore{Core: zapcore.NewNopCore(), started: make(chan struct{}), release: make(chan struct{})}
+	swap.swap(slow, func() { closed.Store(true) })
+	closed.Store(false)
+	go held.Info("in flight")
+	<-slow.started
+
+	done := make(chan struct{})
+	go func() {
+		swap.swap(zapcore.NewNopCore(), nil)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	if closed.Load() {
+		t.Fatal("old core closed while a write was in flight")
+	}
+	close(slow.release)
+	<-done
+	if !closed.Load() {
+		t.Fatal("old core not closed after writes drained")
+	}
+}
@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/ahrtolia/goboot/pkg/logger"
+	redislib "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// loggingHook 将命令执行情况输出到请求级 logger（logger.FromContext），名称为 redis。
+// 失败以 warn 级别输出，成功以 debug 级别输出，可通过 logger.levels.redis 调整
+type loggingHook struct{}
+
+func (loggingHook) DialHook(next redislib.DialHook) redislib.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			logger.FromContext(ctx).Named("redis").Warn("redis dial failed", zap.String("addr", addr), zap.Error(err))
+		}
+		return conn, err
+	}
+}
+
+func (loggingHook) ProcessHook(next redislib.ProcessHook) redislib.ProcessHook {
+	return func(ctx context.Context, cmd redislib.Cmder) error {
+		begin := time.Now()
+		err := next(ctx, cmd)
+		logCommand(ctx, cmd.Name(), 1, time.Since(begin), err)
+		return err
+	}
+}
+
+func (loggingHook) ProcessPipelineHook(next redislib.ProcessPipelineHook) redislib.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redislib.Cmder) error {
+		begin := time.Now()
+		err := next(ctx, cmds)
+		logCommand(ctx, "pipeline", len(cmds), time.Since(begin), err)
+		return err
+	}
+}
+
+func logCommand(ctx context.Context, name string, count int, elapsed time.Duration, err error) {
+	l := logger.FromContext(ctx).Named("redis")
+	fields := []zap.Field{zap.String("cmd", name), zap.Duration("elapsed", elapsed)}
+	if count > 1 {
+		fields = append(fields, zap.Int("count", count))
+	}
+
+	if err != nil && !errors.Is(err, redislib.Nil) {
+		l.Warn("redis command failed", append(fields, zap.Error(err))...)
+		return
+	}
+	l.Debug("redis command", fields...)
+}
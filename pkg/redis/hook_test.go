@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/ahrtolia/goboot/pkg/logger"
+	redislib "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLoggingHook(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	ctx := logger.WithContext(context.Background(), zap.New(core))
+	hook := loggingHook{}
+
+	process := func(err error) redislib.ProcessHook {
+		return hook.ProcessHook(func(ctx context.Context, cmd redislib.Cmder) error { return err })
+	}
+	_ = process(nil)(ctx, redislib.NewStringCmd(ctx, "get", "user:1"))
+	_ = process(redislib.Nil)(ctx, redislib.NewStringCmd(ctx, "get", "missing"))
+	if err := process(errors.New("READONLY"))(ctx, redislib.NewStatusCmd(ctx, "set", "k", "v")); err == nil {
+		t.Error("hook should return the command error")
+	}
+
+	pipeline := hook.ProcessPipelineHook(func(ctx context.Context, cmds []redislib.Cmder) error { return nil })
+	_ = pipeline(ctx, []redislib.Cmder{redislib.NewStringCmd(ctx, "get", "a"), redislib.NewStringCmd(ctx, "get", "b")})
+
+	dial := hook.DialHook(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("connection refused")
+	})
+	_, _ = dial(ctx, "tcp", "127.0.0.1:6379")
+
+	want := []struct {
+		level   zapcore.Level
+		message string
+		cmd     string
+	}{
+		{zapcore.DebugLevel, "redis command", "get"},
+		{zapcore.DebugLevel, "redis command", "get"}, // redis.Nil 不视为失败
+		{zapcore.WarnLevel, "redis command failed", "set"},
+		{zapcore.DebugLevel, "redis command", "pipeline"},
+		{zapcore.WarnLevel, "redis dial failed", ""},
+	}
+	entries := logs.All()
+	if len(entries) != len(want) {
+		t.Fatalf("got %d records, want %d", len(entries), len(want))
+	}
+	for i, w := range want {
+		e := entries[i]
+		fields := e.ContextMap()
+		if e.Level != w.level || e.Message != w.message || e.LoggerName != "redis" {
+			t.Errorf("record[%d] = %s %q from %q, want %s %q", i, e.Level, e.Message, e.LoggerName, w.level, w.message)
+		}
+		if w.cmd != "" && fields["cmd"] != w.cmd {
+			t.Errorf("record[%d] cmd = %v, want %s", i, fields["cmd"], w.cmd)
+		}
+	}
+	if got := entries[3].ContextMap()["count"]; got != int64(2) {
+		t.Errorf("pipeline count = %v, want 2", got)
+	}
+	if got := entries[4].ContextMap()["addr"]; got != "127.0.0.1:6379" {
+		t.Errorf("dial addr = %v", got)
+	}
+}
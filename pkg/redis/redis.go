@@ -17,7 +17,7 @@ import (
 var ErrRedisDisabled = errors.New("redis client is disabled")
 
 type Option struct {
-	Enabled         bool          `mapstructure:"enabled"`
+	Enabled         bool          `mapstructure:"enabled" default:"true"` // 存在 redis 节点时默认启用
 	Addr            string        `mapstructure:"addr"`
 	Username        string        `mapstructure:"username"`
 	Password        string        `mapstructure:"password"`
@@ -34,9 +34,12 @@ type Option struct {
 	PingTimeout     time.Duration `mapstructure:"ping_timeout"`
 }
 
-func NewOption(cfg *config.ConfigManager) (*Option, error) {
-	opt := &Option{
-		Enabled:         cfg.GetViper().InConfig("redis"),
+func init() {
+	config.RegisterOption("redis", defaultOption())
+}
+
+func defaultOption() *Option {
+	return &Option{
 		Addr:            "127.0.0.1:6379",
 		DB:              0,
 		MaxRetries:      3,
@@ -50,6 +53,11 @@ func NewOption(cfg *config.ConfigManager) (*Option, error) {
 		ConnMaxLifetime: 0,
 		PingTimeout:     2 * time.Second,
 	}
+}
+
+func NewOption(cfg *config.ConfigManager) (*Option, error) {
+	opt := defaultOption()
+	opt.Enabled = cfg.GetViper().InConfig("redis")
 
 	v := cfg.GetViper()
 	if redisCfg := v.Sub("redis"); redisCfg != nil {
@@ -70,7 +78,7 @@ type Client struct {
 
 func NewClient(logger *zap.Logger, cfg *config.ConfigManager, opt *Option) (*Client, error) {
 	c := &Client{
-		logger: logger,
+		logger: logger.Named("redis"),
 	}
 
 	if err := c.applyConfig(opt); err != nil {
@@ -110,6 +118,7 @@ func (c *Client) applyConfig(opt *Option) error {
 		ConnMaxIdleTime: opt.ConnMaxIdleTime,
 		ConnMaxLifetime: opt.ConnMaxLifetime,
 	})
+	newClient.AddHook(loggingHook{})
 
 	ctx, cancel := context.WithTimeout(context.Background(), opt.PingTimeout)
 	defer cancel()
@@ -127,21 +136,8 @@ func (c *Client) applyConfig(opt *Option) error {
 }
 
 func (c *Client) ReloadConfig(v *viper.Viper) error {
-	newOpt := &Option{
-		Enabled:         v.InConfig("redis"),
-		Addr:            "127.0.0.1:6379",
-		DB:              0,
-		MaxRetries:      3,
-		DialTimeout:     5 * time.Second,
-		ReadTimeout:     3 * time.Second,
-		WriteTimeout:    3 * time.Second,
-		PoolSize:        10,
-		MinIdleConns:    2,
-		PoolTimeout:     4 * time.Second,
-		ConnMaxIdleTime: 5 * time.Minute,
-		ConnMaxLifetime: 0,
-		PingTimeout:     2 * time.Second,
-	}
+	newOpt := defaultOption()
+	newOpt.Enabled = v.InConfig("redis")
 	if redisCfg := v.Sub("redis"); redisCfg != nil {
 		if err := redisCfg.Unmarshal(newOpt); err != nil {
 			return fmt.Errorf("failed to unmarshal redis options: %w", err)
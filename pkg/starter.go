@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/ahrtolia/goboot/pkg/config"
 	"github.com/ahrtolia/goboot/pkg/cron_starter"
+	"github.com/ahrtolia/goboot/pkg/discovery"
 	"github.com/ahrtolia/goboot/pkg/gin_starter"
 	redispkg "github.com/ahrtolia/goboot/pkg/redis"
 	"go.uber.org/zap"
@@ -11,20 +12,22 @@ import (
 )
 
 type Context struct {
-	Config *config.ConfigManager
-	Logger *zap.Logger
-	HTTP   *gin_starter.Server
-	DB     *gorm.DB
-	Cron   *cron_starter.Scheduler
-	Redis  *redispkg.Client
+	Config    *config.ConfigManager
+	Logger    *zap.Logger
+	HTTP      *gin_starter.Server
+	DB        *gorm.DB
+	Cron      *cron_starter.Scheduler
+	Redis     *redispkg.Client
+	Discovery *discovery.Registry
 }
 
-func NewContext(cfg *config.ConfigManager, logger *zap.Logger, httpSrv *gin_starter.Server, db *gorm.DB, cronScheduler *cron_starter.Scheduler, redisClient *redispkg.Client) *Context {
+func NewContext(cfg *config.ConfigManager, logger *zap.Logger, httpSrv *gin_starter.Server, db *gorm.DB, cronScheduler *cron_starter.Scheduler, redisClient *redispkg.Client, registry *discovery.Registry) *Context {
 	return &Context{
-		Config: cfg,
-		Logger: logger,
-		HTTP:   httpSrv,
-		DB:     db,
+		Config:    cfg,
+		Logger:    logger,
+		HTTP:      httpSrv,
+		DB:        db,
+		Discovery: registry,
 	}
 }
 
@@ -42,10 +45,14 @@ func NewStarters(
 	gormStarter *GormStarter,
 	cronStarter *CronStarter,
 	redisStarter *RedisStarter,
+	discoveryStarter *DiscoveryStarter,
+	adminStarter *AdminStarter,
 ) []Starter {
 	return []Starter{
 		loggerStarter,
+		adminStarter,
 		httpStarter,
 		gormStarter,
+		discoveryStarter,
 	}
 }
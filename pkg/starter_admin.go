@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+
+	"github.com/ahrtolia/goboot/pkg/admin"
+	"github.com/ahrtolia/goboot/pkg/config"
+	"github.com/ahrtolia/goboot/pkg/gin_starter"
+)
+
+type AdminStarter struct {
+	cfg    *config.ConfigManager
+	admin  *admin.Admin
+	server *gin_starter.Server
+}
+
+func NewAdminStarter(cfg *config.ConfigManager, adm *admin.Admin, server *gin_starter.Server) *AdminStarter {
+	return &AdminStarter{
+		cfg:    cfg,
+		admin:  adm,
+		server: server,
+	}
+}
+
+func (s *AdminStarter) Name() string {
+	return "admin"
+}
+
+func (s *AdminStarter) Enabled(ctx *Context) bool {
+	return enabledByConfig(ctx, "admin.enabled", "", false)
+}
+
+// Init 在 HTTP 服务启动前挂载管理接口
+func (s *AdminStarter) Init(ctx *Context) error {
+	if s.admin == nil || s.server == nil {
+		return nil
+	}
+	s.server.RegisterRoutes(s.admin.Mount)
+	return nil
+}
+
+func (s *AdminStarter) Start(ctx *Context) error {
+	return nil
+}
+
+func (s *AdminStarter) Stop(_ context.Context, _ *Context) error {
+	return nil
+}
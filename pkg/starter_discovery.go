@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahrtolia/goboot/pkg/config"
+	"github.com/ahrtolia/goboot/pkg/discovery"
+	"github.com/ahrtolia/goboot/pkg/gin_starter"
+	"github.com/spf13/viper"
+)
+
+type DiscoveryStarter struct {
+	cfg      *config.ConfigManager
+	registry *discovery.Registry
+	server   *gin_starter.Server
+}
+
+func NewDiscoveryStarter(cfg *config.ConfigManager, registry *discovery.Registry, server *gin_starter.Server) *DiscoveryStarter {
+	return &DiscoveryStarter{
+		cfg:      cfg,
+		registry: registry,
+		server:   server,
+	}
+}
+
+func (s *DiscoveryStarter) Name() string {
+	return "discovery"
+}
+
+func (s *DiscoveryStarter) Enabled(ctx *Context) bool {
+	return enabledByConfig(ctx, "discovery.enabled", "discovery", false)
+}
+
+func (s *DiscoveryStarter) Init(ctx *Context) error {
+	return nil
+}
+
+// Start 在 HTTP 服务启动后注册实例，之后随 discovery 与 http 配置的重载更新注册信息
+func (s *DiscoveryStarter) Start(ctx *Context) error {
+	if s.registry == nil || !s.registry.Enabled() || s.server == nil {
+		return nil
+	}
+	opt := s.server.GetOption()
+	if err := s.registry.Register(fmt.Sprintf("%s:%d", opt.Addr, opt.Port)); err != nil {
+		return err
+	}
+	return s.cfg.RegisterReloader("discovery", config.ConfigReloaderFunc(s.reload))
+}
+
+// reload 按新的配置重新计算注册信息。http 端口变化时 gin_starter 在新端口重新监听，
+// 各 reloader 并发执行，因此直接从配置读取监听地址而不是从 server 获取
+func (s *DiscoveryStarter) reload(v *viper.Viper) error {
+	opt, err := discovery.LoadOption(v)
+	if err != nil {
+		return err
+	}
+	httpOpt, err := gin_starter.LoadOption(v)
+	if err != nil {
+		return err
+	}
+	return s.registry.Update(opt, fmt.Sprintf("%s:%d", httpOpt.Addr, httpOpt.Port))
+}
+
+// Stop 先于 HTTP 服务停止执行，保证实例在停止接收流量前注销
+func (s *DiscoveryStarter) Stop(_ context.Context, _ *Context) error {
+	if s.registry == nil {
+		return nil
+	}
+	defer s.registry.Close()
+	return s.registry.Deregister()
+}